@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// sprintStates lists the sprint states in the order they are shown.
+var sprintStates = []struct {
+	state string
+	label string
+}{
+	{"active", "Active"},
+	{"future", "Future"},
+	{"closed", "Closed"},
+}
+
+// showBoardBrowser shows the boards on the left and the sprints of the
+// selected board on the right. Picking a sprint calls onSprintSelected.
+// boards caches the board list for the session.
+func showBoardBrowser(ctx context.Context, app *tview.Application, client *JiraClient, boards *[]Board, returnToMain func(), updateStatusFunc func(message string, isError bool), onSprintSelected func(Board, Sprint)) {
+	if *boards != nil {
+		buildBoardBrowser(ctx, app, client, *boards, returnToMain, updateStatusFunc, onSprintSelected)
+		return
+	}
+	go func() {
+		updateStatusFunc("Fetching boards...", false)
+		fetched, err := client.FetchJiraBoards(ctx)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				updateStatusFunc(fmt.Sprintf("Error fetching boards: %s", describeError(err)), true)
+				return
+			}
+			if len(fetched) == 0 {
+				updateStatusFunc("No boards found.", true)
+				return
+			}
+			updateStatusFunc(fmt.Sprintf("Loaded %d boards.", len(fetched)), false)
+			*boards = fetched
+			buildBoardBrowser(ctx, app, client, fetched, returnToMain, updateStatusFunc, onSprintSelected)
+		})
+	}()
+}
+
+func buildBoardBrowser(ctx context.Context, app *tview.Application, client *JiraClient, boards []Board, returnToMain func(), updateStatusFunc func(message string, isError bool), onSprintSelected func(Board, Sprint)) {
+	filterField := tview.NewInputField().SetLabel("Filter: ")
+	filterField.SetFieldBackgroundColor(tcell.ColorDefault)
+	filterField.SetLabelColor(tcell.ColorAqua)
+
+	boardList := tview.NewList().ShowSecondaryText(false)
+	boardList.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+
+	sprintList := tview.NewList()
+	sprintList.SetBorder(true).SetTitle("Sprints")
+	sprintList.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+	sprintList.SetSecondaryTextColor(tcell.ColorGray)
+	sprintList.AddItem("[gray]Select a board to list its sprints.", "", 0, nil)
+
+	boardPane := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(filterField, 1, 0, true).
+		AddItem(boardList, 0, 1, false)
+	boardPane.SetBorder(true).SetTitle("Boards (Enter to list sprints, Esc to close)")
+
+	var shown []Board
+	refreshBoards := func(filter string) {
+		boardList.Clear()
+		shown = nil
+		for _, b := range boards {
+			if _, _, ok := fuzzyMatch(filter, b.Name); ok {
+				shown = append(shown, b)
+				boardList.AddItem(escapeTags(b.Name), "", 0, nil)
+			}
+		}
+	}
+	refreshBoards("")
+
+	// sprintRows maps sprint list rows to sprints; state headers map to nil.
+	var sprintRows []*Sprint
+	var selectedBoard Board
+	showSprints := func(board Board) {
+		selectedBoard = board
+		sprintList.Clear()
+		sprintList.AddItem("[gray]Loading sprints...", "", 0, nil)
+		sprintRows = nil
+		go func() {
+			sprints, err := client.FetchJiraSprints(ctx, board.ID)
+			app.QueueUpdateDraw(func() {
+				if selectedBoard.ID != board.ID {
+					return // another board was picked in the meantime
+				}
+				sprintList.Clear()
+				if err != nil {
+					sprintList.AddItem("[red]"+escapeTags(describeError(err)), "", 0, nil)
+					return
+				}
+				sprintList.SetTitle(fmt.Sprintf("Sprints of %s (Enter to open)", escapeTags(board.Name)))
+				for _, group := range sprintStates {
+					var inState []Sprint
+					for _, s := range sprints {
+						if strings.EqualFold(s.State, group.state) {
+							inState = append(inState, s)
+						}
+					}
+					if len(inState) == 0 {
+						continue
+					}
+					if group.state == "closed" {
+						// Most recently closed first.
+						sort.SliceStable(inState, func(i, j int) bool {
+							return inState[i].CompleteDate.After(inState[j].CompleteDate.Time)
+						})
+					}
+					sprintList.AddItem(fmt.Sprintf("[yellow]%s (%d)", group.label, len(inState)), "", 0, nil)
+					sprintRows = append(sprintRows, nil)
+					for i := range inState {
+						s := inState[i]
+						sprintList.AddItem("  "+escapeTags(s.Name), "  "+formatSprintDates(s), 0, nil)
+						sprintRows = append(sprintRows, &s)
+					}
+				}
+				if len(sprintRows) == 0 {
+					sprintList.AddItem("[gray]This board has no sprints.", "", 0, nil)
+					return
+				}
+				sprintList.SetCurrentItem(min(1, len(sprintRows)-1))
+				app.SetFocus(sprintList)
+			})
+		}()
+	}
+
+	selectBoard := func() {
+		index := boardList.GetCurrentItem()
+		if index >= 0 && index < len(shown) {
+			showSprints(shown[index])
+		}
+	}
+
+	filterField.SetChangedFunc(refreshBoards)
+	filterField.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			returnToMain()
+		case tcell.KeyEnter:
+			selectBoard()
+		case tcell.KeyTab, tcell.KeyDown:
+			app.SetFocus(boardList)
+		}
+	})
+	boardList.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		selectBoard()
+	})
+	boardList.SetDoneFunc(returnToMain)
+	boardList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyBacktab || (event.Key() == tcell.KeyUp && boardList.GetCurrentItem() == 0) {
+			app.SetFocus(filterField)
+			return nil
+		}
+		if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyRight {
+			app.SetFocus(sprintList)
+			return nil
+		}
+		return event
+	})
+
+	sprintList.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		if index < 0 || index >= len(sprintRows) || sprintRows[index] == nil {
+			return
+		}
+		returnToMain()
+		onSprintSelected(selectedBoard, *sprintRows[index])
+	})
+	sprintList.SetDoneFunc(func() {
+		app.SetFocus(boardList)
+	})
+	sprintList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyBacktab || event.Key() == tcell.KeyLeft {
+			app.SetFocus(boardList)
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().
+		AddItem(boardPane, 0, 1, true).
+		AddItem(sprintList, 0, 1, false)
+	app.SetRoot(layout, true).SetFocus(filterField)
+}
+
+func formatSprintDates(s Sprint) string {
+	if s.StartDate.IsZero() {
+		return "not started"
+	}
+	return fmt.Sprintf("%s → %s", s.StartDate.Format("2006-01-02"), s.EndDate.Format("2006-01-02"))
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxIncrementalAge is how old a cache may be for a sync to fetch only the
+// issues updated since; older caches are refreshed with a full fetch.
+const maxIncrementalAge = 7 * 24 * time.Hour
+
+// cachedQuery is the result of a JQL query as stored on disk, so that the
+// list can be shown straight away on the next start.
+type cachedQuery struct {
+	Site     string    `json:"site"`
+	JQL      string    `json:"jql"`
+	LastSync time.Time `json:"lastSync"` // when the fetch that filled Issues started
+	Issues   []Issue   `json:"issues"`
+}
+
+// cachePath returns the file caching the results of jql on site.
+func cachePath(site, jql string) string {
+	sum := sha256.Sum256([]byte(site + "\n" + jql))
+	return filepath.Join(appCacheDir(), "issues", hex.EncodeToString(sum[:12])+".json")
+}
+
+// loadCachedQuery returns the cached results of jql on site, if any.
+func loadCachedQuery(site, jql string) (cachedQuery, bool) {
+	var cached cachedQuery
+	data, err := os.ReadFile(cachePath(site, jql))
+	if err != nil {
+		return cached, false
+	}
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Site != site || cached.JQL != jql {
+		return cachedQuery{}, false
+	}
+	return cached, true
+}
+
+// save writes the cache to disk.
+func (c cachedQuery) save() error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return fmt.Errorf("error encoding issue cache: %w", err)
+	}
+	if err := writeFileAtomic(cachePath(c.Site, c.JQL), data); err != nil {
+		return fmt.Errorf("error writing issue cache: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data through a temporary file, so that a crash never
+// leaves a half written file behind.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// transitionsPath is the file remembering the transitions Jira offered.
+func transitionsPath() string {
+	return filepath.Join(appCacheDir(), "transitions.json")
+}
+
+// transitionsKey identifies the workflow step of an issue: issues of the same
+// project and type in the same status are offered the same transitions.
+func transitionsKey(site string, issue Issue) string {
+	project, _, _ := strings.Cut(issue.Key, "-")
+	return strings.Join([]string{site, project, issue.Fields.IssueType.Name, issue.Fields.Status.Name}, "|")
+}
+
+// loadCachedTransitions returns the transitions last offered for an issue at
+// the same workflow step as issue, so that it can be moved while offline.
+func loadCachedTransitions(site string, issue Issue) ([]Transition, bool) {
+	cached := map[string][]Transition{}
+	data, err := os.ReadFile(transitionsPath())
+	if err != nil || json.Unmarshal(data, &cached) != nil {
+		return nil, false
+	}
+	transitions, ok := cached[transitionsKey(site, issue)]
+	return transitions, ok && len(transitions) > 0
+}
+
+// saveCachedTransitions remembers the transitions offered for issue.
+func saveCachedTransitions(site string, issue Issue, transitions []Transition) error {
+	cached := map[string][]Transition{}
+	if data, err := os.ReadFile(transitionsPath()); err == nil {
+		json.Unmarshal(data, &cached) // start afresh if unreadable
+	}
+	cached[transitionsKey(site, issue)] = transitions
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return fmt.Errorf("error encoding transitions cache: %w", err)
+	}
+	if err := writeFileAtomic(transitionsPath(), data); err != nil {
+		return fmt.Errorf("error writing transitions cache: %w", err)
+	}
+	return nil
+}
+
+// mergeIssues applies updated issues to issues: known ones are replaced in
+// place, new ones are put first in the order received. orderIssues puts them
+// back in the query's order once its keys are known.
+func mergeIssues(issues, updates []Issue) []Issue {
+	index := make(map[string]int, len(issues))
+	for i, issue := range issues {
+		index[issue.Key] = i
+	}
+	merged := append([]Issue(nil), issues...)
+	var added []Issue
+	for _, u := range updates {
+		if i, ok := index[u.Key]; ok {
+			merged[i] = u
+		} else {
+			added = append(added, u)
+		}
+	}
+	return append(added, merged...)
+}
+
+// orderIssues sorts issues in the order of keys, as returned by the query,
+// and drops those whose key is not among them, e.g. those that left the query
+// since it was cached. It returns how many it dropped.
+func orderIssues(issues []Issue, keys []string) ([]Issue, int) {
+	byKey := make(map[string]Issue, len(issues))
+	for _, issue := range issues {
+		byKey[issue.Key] = issue
+	}
+	var ordered []Issue
+	for _, key := range keys {
+		if issue, ok := byKey[key]; ok {
+			ordered = append(ordered, issue)
+			delete(byKey, key)
+		}
+	}
+	return ordered, len(byKey)
+}
+
+// splitOrderBy splits a JQL query into its condition and its ORDER BY
+// clause, which is "" when there is none.
+func splitOrderBy(jql string) (string, string) {
+	tokens := tokenizeJQL(jql)
+	for i := 0; i+1 < len(tokens); i++ {
+		if strings.EqualFold(tokens[i].text, "order") && strings.EqualFold(tokens[i+1].text, "by") {
+			return strings.TrimSpace(jql[:tokens[i].start]), strings.TrimSpace(jql[tokens[i].start:])
+		}
+	}
+	return strings.TrimSpace(jql), ""
+}
+
+// updatedSinceJQL restricts jql to the issues updated since t. The time is
+// given relative to now so that Jira's and the user's time zones don't
+// matter, with a minute of slack for clock skew.
+func updatedSinceJQL(jql string, t time.Time) string {
+	where, orderBy := splitOrderBy(jql)
+	since := fmt.Sprintf("updated >= -%dm", int(time.Since(t).Minutes())+2)
+	if where != "" {
+		since = fmt.Sprintf("(%s) AND %s", where, since)
+	}
+	if orderBy != "" {
+		since += " " + orderBy
+	}
+	return since
+}
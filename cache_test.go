@@ -0,0 +1,103 @@
+package main
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func issueKeys(issues []Issue) []string {
+	keys := make([]string, len(issues))
+	for i, issue := range issues {
+		keys[i] = issue.Key
+	}
+	return keys
+}
+
+func testIssue(key, summary string) Issue {
+	var issue Issue
+	issue.Key = key
+	issue.Fields.Summary = summary
+	return issue
+}
+
+func TestMergeIssues(t *testing.T) {
+	cached := []Issue{testIssue("A-1", "one"), testIssue("A-2", "two"), testIssue("A-3", "three")}
+	updates := []Issue{testIssue("A-4", "four"), testIssue("A-2", "two, edited"), testIssue("A-5", "five")}
+
+	merged := mergeIssues(cached, updates)
+	if got, want := issueKeys(merged), []string{"A-4", "A-5", "A-1", "A-2", "A-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("keys = %v, want %v", got, want)
+	}
+	if got := merged[3].Fields.Summary; got != "two, edited" {
+		t.Errorf("A-2 summary = %q, want the update", got)
+	}
+	if cached[1].Fields.Summary != "two" {
+		t.Error("mergeIssues modified its input")
+	}
+}
+
+func TestOrderIssues(t *testing.T) {
+	issues := []Issue{testIssue("A-4", ""), testIssue("A-1", ""), testIssue("A-2", ""), testIssue("A-3", "")}
+	tests := []struct {
+		name    string
+		keys    []string
+		want    []string
+		removed int
+	}{
+		{"query order", []string{"A-1", "A-2", "A-3", "A-4"}, []string{"A-1", "A-2", "A-3", "A-4"}, 0},
+		{"reversed", []string{"A-4", "A-3", "A-2", "A-1"}, []string{"A-4", "A-3", "A-2", "A-1"}, 0},
+		{"left the query", []string{"A-3", "A-1"}, []string{"A-3", "A-1"}, 2},
+		{"not fetched", []string{"A-9", "A-2", "A-8"}, []string{"A-2"}, 3},
+		{"none", nil, []string{}, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, removed := orderIssues(issues, tt.keys)
+			if keys := issueKeys(got); !reflect.DeepEqual(keys, tt.want) {
+				t.Errorf("keys = %v, want %v", keys, tt.want)
+			}
+			if removed != tt.removed {
+				t.Errorf("removed = %d, want %d", removed, tt.removed)
+			}
+		})
+	}
+}
+
+func TestSplitOrderBy(t *testing.T) {
+	tests := []struct {
+		jql, where, orderBy string
+	}{
+		{"project = A", "project = A", ""},
+		{"project = A ORDER BY created DESC", "project = A", "ORDER BY created DESC"},
+		{"project = A order   by rank", "project = A", "order   by rank"},
+		{"ORDER BY updated", "", "ORDER BY updated"},
+		{`summary ~ "order by" AND project = A`, `summary ~ "order by" AND project = A`, ""},
+		{`summary ~ "order by" order by key`, `summary ~ "order by"`, "order by key"},
+		{"  ", "", ""},
+	}
+	for _, tt := range tests {
+		where, orderBy := splitOrderBy(tt.jql)
+		if where != tt.where || orderBy != tt.orderBy {
+			t.Errorf("splitOrderBy(%q) = %q, %q, want %q, %q", tt.jql, where, orderBy, tt.where, tt.orderBy)
+		}
+	}
+}
+
+func TestUpdatedSinceJQL(t *testing.T) {
+	since := time.Now().Add(-90 * time.Minute)
+	tests := []struct {
+		jql, want string
+	}{
+		{"project = A", `^\(project = A\) AND updated >= -9[12]m$`},
+		{"project = A OR project = B ORDER BY key", `^\(project = A OR project = B\) AND updated >= -9[12]m ORDER BY key$`},
+		{"order by created", `^updated >= -9[12]m order by created$`},
+		{"", `^updated >= -9[12]m$`},
+	}
+	for _, tt := range tests {
+		if got := updatedSinceJQL(tt.jql, since); !regexp.MustCompile(tt.want).MatchString(got) {
+			t.Errorf("updatedSinceJQL(%q) = %q, want a match for %s", tt.jql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// cliCommand is a non-interactive command, for use in shell scripts, git
+// hooks and CI.
+type cliCommand struct {
+	name  string
+	args  string // synopsis of the arguments
+	about string
+	run   func(ctx context.Context, client *JiraClient, cfg Config, args []string, out io.Writer) error
+}
+
+var cliCommands = []cliCommand{
+	{"list", "[--jql JQL] [--json]", "list the issues of a JQL query (by default the configured one)", cmdList},
+	{"view", "KEY [--json]", "show an issue with its description and comments", cmdView},
+	{"transition", "KEY STATUS [-m COMMENT]", "move an issue through the transition named STATUS or leading to it", cmdTransition},
+	{"comment", "KEY [-m TEXT]", "add a comment, read from stdin without -m", cmdComment},
+	{"assign", "KEY USER", "assign an issue; USER is me, none, or a name or email", cmdAssign},
+	{"branch", "KEY", "print the git branch name for an issue", cmdBranch},
+}
+
+// commandsUsage lists the commands for the usage message.
+func commandsUsage() string {
+	var b strings.Builder
+	b.WriteString("Commands:\n")
+	for _, c := range cliCommands {
+		fmt.Fprintf(&b, "  %s %s\n    \t%s\n", c.name, c.args, c.about)
+	}
+	return b.String()
+}
+
+// usageError is returned for invalid command arguments.
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// runCommand runs the command named by args[0] and returns the exit status:
+// 0 on success, 1 when it failed and 2 for invalid arguments.
+func runCommand(ctx context.Context, client *JiraClient, cfg Config, args []string, out, errOut io.Writer) int {
+	for _, c := range cliCommands {
+		if c.name != args[0] {
+			continue
+		}
+		err := c.run(ctx, client, cfg, args[1:], out)
+		var usageErr usageError
+		switch {
+		case err == nil:
+			return 0
+		case errors.Is(err, flag.ErrHelp):
+			return 0
+		case errors.As(err, &usageErr):
+			fmt.Fprintf(errOut, "Error: %v\nUsage: jira %s %s\n", err, c.name, c.args)
+			return 2
+		default:
+			fmt.Fprintf(errOut, "Error: %s\n", describeError(err))
+			return 1
+		}
+	}
+	fmt.Fprintf(errOut, "Error: unknown command %q\n\n%s", args[0], commandsUsage())
+	return 2
+}
+
+// parseCommandArgs parses the flags of a command wherever they are among its
+// arguments, so that "comment KEY -m text" works as well as "comment -m text
+// KEY", and checks that there are count positional arguments.
+func parseCommandArgs(fs *flag.FlagSet, args []string, count int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		} else if err != nil {
+			return nil, usageError(err.Error())
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(positional) != count {
+		return nil, usageError(fmt.Sprintf("expected %d arguments, got %d", count, len(positional)))
+	}
+	return positional, nil
+}
+
+func commandFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("jira "+name, flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+	return fs
+}
+
+func writeJSON(out io.Writer, v interface{}) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// cmdList prints one issue per line as tab separated key, status, assignee
+// and summary.
+func cmdList(ctx context.Context, client *JiraClient, cfg Config, args []string, out io.Writer) error {
+	fs := commandFlags("list")
+	jql := fs.String("jql", cfg.JQL, "JQL query")
+	asJSON := fs.Bool("json", false, "print the issues as JSON")
+	if _, err := parseCommandArgs(fs, args, 0); err != nil {
+		return err
+	}
+	issues, err := client.FetchJiraIssues(ctx, *jql, nil)
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(out, issues)
+	}
+	for _, issue := range issues {
+		assignee := "-"
+		if issue.Fields.Assignee != nil {
+			assignee = issue.Fields.Assignee.DisplayName
+		}
+		fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", issue.Key, issue.Fields.Status.Name, assignee, issue.Fields.Summary)
+	}
+	return nil
+}
+
+func cmdView(ctx context.Context, client *JiraClient, cfg Config, args []string, out io.Writer) error {
+	fs := commandFlags("view")
+	asJSON := fs.Bool("json", false, "print the issue as JSON")
+	positional, err := parseCommandArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	issue, err := client.FetchIssue(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return writeJSON(out, issue)
+	}
+
+	name := func(u *User) string {
+		if u == nil {
+			return "-"
+		}
+		return u.DisplayName
+	}
+	priority := "-"
+	if issue.Fields.Priority != nil {
+		priority = issue.Fields.Priority.Name
+	}
+	fmt.Fprintf(out, "%s: %s\n\n", issue.Key, issue.Fields.Summary)
+	fmt.Fprintf(out, "Status:    %s\n", issue.Fields.Status.Name)
+	fmt.Fprintf(out, "Type:      %s\n", issue.Fields.IssueType.Name)
+	fmt.Fprintf(out, "Priority:  %s\n", priority)
+	fmt.Fprintf(out, "Assignee:  %s\n", name(issue.Fields.Assignee))
+	fmt.Fprintf(out, "Reporter:  %s\n", name(issue.Fields.Reporter))
+	if len(issue.Fields.Labels) > 0 {
+		fmt.Fprintf(out, "Labels:    %s\n", strings.Join(issue.Fields.Labels, ", "))
+	}
+	fmt.Fprintf(out, "Created:   %s\n", issue.Fields.Created.Format("2006-01-02 15:04"))
+	fmt.Fprintf(out, "Updated:   %s\n", issue.Fields.Updated.Format("2006-01-02 15:04"))
+	fmt.Fprintf(out, "URL:       %s\n", client.BrowseURL(issue.Key))
+	if description := strings.TrimSpace(richTextPlain(issue.Fields.Description)); description != "" {
+		fmt.Fprintf(out, "\n%s\n", description)
+	}
+	if issue.Fields.Comments != nil && len(issue.Fields.Comments.Comments) > 0 {
+		fmt.Fprintf(out, "\nComments:\n")
+		for _, c := range issue.Fields.Comments.Comments {
+			fmt.Fprintf(out, "\n%s, %s:\n", name(c.Author), c.Created.Format("2006-01-02 15:04"))
+			for _, line := range strings.Split(strings.TrimSpace(c.Body), "\n") {
+				fmt.Fprintf(out, "  %s\n", line)
+			}
+		}
+	}
+	return nil
+}
+
+func cmdTransition(ctx context.Context, client *JiraClient, cfg Config, args []string, out io.Writer) error {
+	fs := commandFlags("transition")
+	comment := fs.String("m", "", "comment to add with the transition")
+	positional, err := parseCommandArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	key, target := positional[0], positional[1]
+
+	transitions, err := client.FetchTransitions(ctx, key)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, t := range transitions {
+		if !strings.EqualFold(t.Name, target) && !strings.EqualFold(t.To.Name, target) {
+			names = append(names, t.To.Name)
+			continue
+		}
+		for field, meta := range t.Fields {
+			if meta.Required && !meta.HasDefaultValue && field != "comment" {
+				return fmt.Errorf("the %s transition needs %s, use the interactive UI", t.Name, meta.Name)
+			}
+		}
+		if err := client.TransitionIssue(ctx, key, t.ID, nil, strings.TrimSpace(*comment)); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s moved to %s.\n", key, t.To.Name)
+		return nil
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no transitions available for %s", key)
+	}
+	return fmt.Errorf("no transition of %s leads to %q, available: %s", key, target, strings.Join(names, ", "))
+}
+
+func cmdComment(ctx context.Context, client *JiraClient, cfg Config, args []string, out io.Writer) error {
+	fs := commandFlags("comment")
+	message := fs.String("m", "", "comment text, - or none to read it from stdin")
+	positional, err := parseCommandArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	body := *message
+	if body == "" || body == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("error reading comment: %w", err)
+		}
+		body = string(data)
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return usageError("comment is empty")
+	}
+	if _, err := client.AddComment(ctx, positional[0], body); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Comment added to %s.\n", positional[0])
+	return nil
+}
+
+func cmdAssign(ctx context.Context, client *JiraClient, cfg Config, args []string, out io.Writer) error {
+	positional, err := parseCommandArgs(commandFlags("assign"), args, 2)
+	if err != nil {
+		return err
+	}
+	key, who := positional[0], positional[1]
+
+	var user *User
+	switch strings.ToLower(who) {
+	case "none":
+	case "me":
+		me, err := client.FetchMyself(ctx)
+		if err != nil {
+			return err
+		}
+		user = &me
+	default:
+		if user, err = findAssignee(ctx, client, key, who); err != nil {
+			return err
+		}
+	}
+	if err := client.AssignIssue(ctx, key, user); err != nil {
+		return err
+	}
+	if user == nil {
+		fmt.Fprintf(out, "%s is now unassigned.\n", key)
+	} else {
+		fmt.Fprintf(out, "%s assigned to %s.\n", key, user.DisplayName)
+	}
+	return nil
+}
+
+// findAssignee looks up the user assignable to an issue matching who
+// exactly, or the only one matching it partially.
+func findAssignee(ctx context.Context, client *JiraClient, key, who string) (*User, error) {
+	users, err := client.FetchAssignableUsers(ctx, "", key, who)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		for _, id := range []string{u.AccountID, u.Name, u.EmailAddress, u.DisplayName} {
+			if id != "" && strings.EqualFold(id, who) {
+				return &u, nil
+			}
+		}
+	}
+	switch len(users) {
+	case 0:
+		return nil, fmt.Errorf("no user matching %q can be assigned %s", who, key)
+	case 1:
+		return &users[0], nil
+	}
+	var names []string
+	for _, u := range users {
+		names = append(names, u.DisplayName)
+	}
+	return nil, fmt.Errorf("%q matches several users: %s", who, strings.Join(names, ", "))
+}
+
+func cmdBranch(ctx context.Context, client *JiraClient, cfg Config, args []string, out io.Writer) error {
+	positional, err := parseCommandArgs(commandFlags("branch"), args, 1)
+	if err != nil {
+		return err
+	}
+	issue, err := client.FetchIssue(ctx, positional[0])
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out, GenerateBranchName(issue))
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseCommandArgs(t *testing.T) {
+	tests := []struct {
+		args       []string
+		count      int
+		positional []string
+		message    string
+		usage      bool  // whether a usage error is expected
+		err        error // wrapped by the returned error otherwise, if any
+	}{
+		{args: []string{"TEST-1", "-m", "hi"}, count: 1, positional: []string{"TEST-1"}, message: "hi"},
+		{args: []string{"-m", "hi", "TEST-1"}, count: 1, positional: []string{"TEST-1"}, message: "hi"},
+		{args: []string{"TEST-1", "Done", "-m=hi there"}, count: 2, positional: []string{"TEST-1", "Done"}, message: "hi there"},
+		{args: []string{"TEST-1", "--", "-m"}, count: 2, positional: []string{"TEST-1", "-m"}},
+		{args: nil, count: 0},
+		{args: []string{"TEST-1"}, count: 2, usage: true},
+		{args: []string{"TEST-1", "TEST-2"}, count: 1, usage: true},
+		{args: []string{"TEST-1", "-x"}, count: 1, usage: true},
+		{args: []string{"TEST-1", "-m"}, count: 1, usage: true},
+		{args: []string{"-h"}, count: 1, err: flag.ErrHelp},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		message := fs.String("m", "", "")
+		positional, err := parseCommandArgs(fs, tt.args, tt.count)
+
+		var usageErr usageError
+		switch {
+		case tt.err != nil:
+			if !errors.Is(err, tt.err) {
+				t.Errorf("parseCommandArgs(%q) error = %v, want %v", tt.args, err, tt.err)
+			}
+		case tt.usage:
+			if !errors.As(err, &usageErr) {
+				t.Errorf("parseCommandArgs(%q) error = %v, want a usage error", tt.args, err)
+			}
+		case err != nil:
+			t.Errorf("parseCommandArgs(%q) error = %v", tt.args, err)
+		case !reflect.DeepEqual(positional, tt.positional) || *message != tt.message:
+			t.Errorf("parseCommandArgs(%q) = %q with -m %q, want %q with -m %q", tt.args, positional, *message, tt.positional, tt.message)
+		}
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	client, _ := newTestClient(t, func(n int, r *http.Request) testReply {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/TEST-1":
+			return testReply{body: map[string]interface{}{"key": "TEST-1", "fields": map[string]interface{}{"summary": "Fix login"}}}
+		case "/rest/api/2/issue/TEST-1/comment":
+			return testReply{status: http.StatusCreated, body: map[string]string{"id": "10"}}
+		}
+		return testReply{status: http.StatusNotFound, body: map[string][]string{"errorMessages": {"Issue does not exist or you do not have permission to see it."}}}
+	})
+	tests := []struct {
+		args   []string
+		status int
+		out    string // start of the output
+		errOut string // start of the error output
+	}{
+		{args: []string{"branch", "TEST-1"}, status: 0, out: "feature/TEST-1-fix-login\n"},
+		{args: []string{"comment", "TEST-1", "-m", "Deployed"}, status: 0, out: "Comment added to TEST-1.\n"},
+		{args: []string{"branch", "TEST-2"}, status: 1, errOut: "Error: Issue does not exist"},
+		{args: []string{"branch"}, status: 2, errOut: "Error: expected 1 arguments, got 0\nUsage: jira branch KEY\n"},
+		{args: []string{"comment", "TEST-1", "-m", "  "}, status: 2, errOut: "Error: comment is empty\n"},
+		{args: []string{"comment", "TEST-1", "-x"}, status: 2, errOut: "Error: flag provided but not defined: -x\n"},
+		{args: []string{"frobnicate"}, status: 2, errOut: "Error: unknown command \"frobnicate\"\n\nCommands:\n"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			var out, errOut bytes.Buffer
+			status := runCommand(context.Background(), client, Config{}, tt.args, &out, &errOut)
+			if status != tt.status {
+				t.Errorf("status = %d, want %d (errors: %q)", status, tt.status, errOut.String())
+			}
+			if !strings.HasPrefix(out.String(), tt.out) || tt.out == "" && out.Len() > 0 {
+				t.Errorf("output = %q, want %q", out.String(), tt.out)
+			}
+			if !strings.HasPrefix(errOut.String(), tt.errOut) || tt.errOut == "" && errOut.Len() > 0 {
+				t.Errorf("error output = %q, want %q", errOut.String(), tt.errOut)
+			}
+		})
+	}
+}
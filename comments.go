@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func createCommentList() *tview.List {
+	commentList := tview.NewList()
+	commentList.SetBorder(true).SetTitle("Comments (c to focus, Enter to read, e to edit, d to delete)")
+	commentList.SetBorderColor(tcell.ColorDarkCyan)
+	commentList.SetTitleColor(tcell.ColorAqua)
+	commentList.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+	commentList.SetSecondaryTextColor(tcell.ColorGray)
+	return commentList
+}
+
+// renderComments fills commentList with the comments of issue, marking the
+// ones isMine says belong to the current user.
+func renderComments(commentList *tview.List, issue Issue, isMine func(Comment) bool) {
+	commentList.Clear()
+	if issue.Fields.Comments == nil || len(issue.Fields.Comments.Comments) == 0 {
+		commentList.AddItem("[gray]No comments.", "", 0, nil)
+		return
+	}
+	for _, comment := range issue.Fields.Comments.Comments {
+		author := "Unknown"
+		if comment.Author != nil {
+			author = comment.Author.DisplayName
+		}
+		mine := ""
+		switch {
+		case comment.ID == "":
+			author, mine = "You", " [yellow](queued)"
+		case isMine(comment):
+			mine = " [yellow](you)"
+		}
+		header := fmt.Sprintf("[white]%s%s [gray]%s", escapeTags(author), mine, comment.Created.Format("2006-01-02 15:04"))
+		commentList.AddItem(header, strings.Join(strings.Fields(renderWiki(comment.Body)), " "), 0, nil)
+	}
+}
+
+// setupCommentList wires the key bindings of the comment list: reading,
+// editing and deleting the selected comment. currentIssue returns the issue
+// whose comments are shown.
+func setupCommentList(ctx context.Context, app *tview.Application, client *JiraClient, box *outbox, mainFlex *tview.Flex, list *tview.List, commentList *tview.List, currentIssue func() (Issue, bool), isMine func(Comment) bool, updateStatusFunc func(message string, isError bool), onChanged func(Issue)) {
+	returnToComments := func() {
+		app.SetRoot(mainFlex, true).SetFocus(commentList)
+	}
+
+	selectedComment := func() (Issue, Comment, bool) {
+		issue, ok := currentIssue()
+		if !ok || issue.Fields.Comments == nil {
+			return issue, Comment{}, false
+		}
+		index := commentList.GetCurrentItem()
+		if index < 0 || index >= len(issue.Fields.Comments.Comments) {
+			return issue, Comment{}, false
+		}
+		return issue, issue.Fields.Comments.Comments[index], true
+	}
+
+	commentList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape, tcell.KeyTab, tcell.KeyBacktab:
+			app.SetFocus(list)
+			return nil
+		case tcell.KeyEnter:
+			if issue, comment, ok := selectedComment(); ok {
+				showCommentViewer(app, issue, comment, returnToComments)
+			}
+			return nil
+		}
+
+		switch event.Rune() {
+		case 'e', 'd':
+			issue, comment, ok := selectedComment()
+			if !ok {
+				return nil
+			}
+			if comment.ID == "" {
+				updateStatusFunc("Queued comments can't be changed before they are sent.", true)
+				return nil
+			}
+			if !isMine(comment) {
+				updateStatusFunc("You can only change your own comments.", true)
+				return nil
+			}
+			if event.Rune() == 'e' {
+				showCommentEditor(ctx, app, client, box, issue, &comment, returnToComments, updateStatusFunc, onChanged)
+			} else {
+				confirmDeleteComment(ctx, app, client, issue, comment, returnToComments, updateStatusFunc, onChanged)
+			}
+			return nil
+		}
+		return event
+	})
+}
+
+// showCommentViewer shows the full text of a comment.
+func showCommentViewer(app *tview.Application, issue Issue, comment Comment, returnTo func()) {
+	author := "Unknown"
+	if comment.Author != nil {
+		author = comment.Author.DisplayName
+	}
+	viewer := tview.NewTextView().
+		SetDynamicColors(true).
+		SetWrap(true).
+		SetWordWrap(true).
+		SetScrollable(true).
+		SetText(renderWiki(comment.Body))
+	viewer.SetBorder(true).SetTitle(fmt.Sprintf("%s: %s, %s (Esc to close)", escapeTags(issue.Key), escapeTags(author), comment.Created.Format("2006-01-02 15:04")))
+	viewer.SetDoneFunc(func(key tcell.Key) {
+		returnTo()
+	})
+	app.SetRoot(centered(viewer, 100, 30), true).SetFocus(viewer)
+}
+
+// confirmDeleteComment asks for confirmation and deletes comment.
+func confirmDeleteComment(ctx context.Context, app *tview.Application, client *JiraClient, issue Issue, comment Comment, returnTo func(), updateStatusFunc func(message string, isError bool), onDeleted func(Issue)) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Delete this comment from %s?\n\n%s", escapeTags(issue.Key), escapeTags(truncate(comment.Body, 200)))).
+		AddButtons([]string{"Delete", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			returnTo()
+			if buttonLabel != "Delete" {
+				return
+			}
+			go func() {
+				updateStatusFunc("Deleting comment...", false)
+				err := client.DeleteComment(ctx, issue.Key, comment.ID)
+				app.QueueUpdateDraw(func() {
+					if err != nil {
+						updateStatusFunc(fmt.Sprintf("Error deleting comment: %s", describeError(err)), true)
+						return
+					}
+					onDeleted(withoutComment(issue, comment.ID))
+					updateStatusFunc(fmt.Sprintf("Comment deleted from %s.", issue.Key), false)
+				})
+			}()
+		})
+	app.SetRoot(modal, false).SetFocus(modal)
+}
+
+// showCommentEditor opens a form to write a new comment on issue, or to edit
+// existing when it is not nil. The text can be written in place or in
+// $EDITOR, and users can be mentioned from a picker. New comments are queued
+// in box while Jira is unreachable. onSaved receives the issue with the
+// comment added or replaced.
+func showCommentEditor(ctx context.Context, app *tview.Application, client *JiraClient, box *outbox, issue Issue, existing *Comment, returnTo func(), updateStatusFunc func(message string, isError bool), onSaved func(Issue)) {
+	textArea := tview.NewTextArea().
+		SetLabel("Comment").
+		SetSize(10, 0).
+		SetPlaceholder("Write your comment...")
+	title := fmt.Sprintf("Comment on %s", issue.Key)
+	if existing != nil {
+		textArea.SetText(existing.Body, true)
+		title = fmt.Sprintf("Edit comment on %s", issue.Key)
+	}
+
+	form := tview.NewForm().AddFormItem(textArea)
+	form.SetBorder(true).SetTitle(title)
+
+	root := centered(form, 80, 16)
+	backToEditor := func() {
+		app.SetRoot(root, true).SetFocus(textArea)
+	}
+
+	var users []User
+	form.AddButton("Save", func() {
+		body := strings.TrimSpace(textArea.GetText())
+		if body == "" {
+			updateStatusFunc("Comment is empty.", true)
+			return
+		}
+		returnTo()
+		go func() {
+			updateStatusFunc(fmt.Sprintf("Saving comment on %s...", issue.Key), false)
+			var comment Comment
+			var err error
+			if existing != nil {
+				comment, err = client.UpdateComment(ctx, issue.Key, existing.ID, body)
+			} else {
+				comment, err = client.AddComment(ctx, issue.Key, body)
+			}
+			app.QueueUpdateDraw(func() {
+				if existing == nil && isUnreachable(err) && box.queue(client, issue, outboxOp{Kind: opComment, Body: body}, updateStatusFunc) {
+					// Until it is sent the comment has no ID.
+					onSaved(withComment(issue, Comment{Body: body, Created: CustomTime{time.Now()}}))
+					return
+				}
+				if err != nil {
+					updateStatusFunc(fmt.Sprintf("Error saving comment: %s", describeError(err)), true)
+					return
+				}
+				onSaved(withComment(issue, comment))
+				updateStatusFunc(fmt.Sprintf("Comment saved on %s.", issue.Key), false)
+			})
+		}()
+	})
+	form.AddButton("Mention", func() {
+		pick := func() {
+			showUserPicker(app, "Mention (Esc to go back)", users, nil, func(u User) {
+				_, start, end := textArea.GetSelection()
+				textArea.Replace(start, end, u.Mention()+" ")
+				backToEditor()
+			}, backToEditor)
+		}
+		if users != nil {
+			pick()
+			return
+		}
+		go func() {
+			updateStatusFunc("Fetching users...", false)
+			fetched, err := client.FetchJiraUsers(ctx)
+			app.QueueUpdateDraw(func() {
+				if err != nil {
+					updateStatusFunc(fmt.Sprintf("Error fetching users: %s", describeError(err)), true)
+					return
+				}
+				updateStatusFunc(fmt.Sprintf("Loaded %d users.", len(fetched)), false)
+				users = fetched
+				pick()
+			})
+		}()
+	})
+	form.AddButton("Editor", func() {
+		text, err := EditInEditor(app, textArea.GetText())
+		if err != nil {
+			updateStatusFunc(fmt.Sprintf("Error opening editor: %v", err), true)
+		}
+		textArea.SetText(text, true)
+		backToEditor()
+	})
+	form.AddButton("Cancel", returnTo)
+	form.SetCancelFunc(returnTo)
+
+	backToEditor()
+}
+
+// withComment returns a copy of issue with comment replacing the comment of
+// the same ID, or appended if there is none or it has no ID yet.
+func withComment(issue Issue, comment Comment) Issue {
+	comments := Comments{}
+	if issue.Fields.Comments != nil {
+		comments = *issue.Fields.Comments
+	}
+	comments.Comments = append([]Comment(nil), comments.Comments...)
+	for i := range comments.Comments {
+		if comment.ID != "" && comments.Comments[i].ID == comment.ID {
+			comments.Comments[i] = comment
+			issue.Fields.Comments = &comments
+			return issue
+		}
+	}
+	comments.Comments = append(comments.Comments, comment)
+	comments.Total++
+	issue.Fields.Comments = &comments
+	return issue
+}
+
+// withoutComment returns a copy of issue without the comment with the given ID.
+func withoutComment(issue Issue, commentID string) Issue {
+	if issue.Fields.Comments == nil {
+		return issue
+	}
+	comments := *issue.Fields.Comments
+	comments.Comments = nil
+	for _, comment := range issue.Fields.Comments.Comments {
+		if comment.ID == commentID {
+			comments.Total--
+			continue
+		}
+		comments.Comments = append(comments.Comments, comment)
+	}
+	issue.Fields.Comments = &comments
+	return issue
+}
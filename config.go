@@ -0,0 +1,313 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// --- Configuration ---
+
+const (
+	authTypeBasic  = "basic"  // email + API token (Jira Cloud) or username + password (Server)
+	authTypeBearer = "bearer" // personal access token (Jira Server / Data Center)
+
+	defaultJQL = "assignee = currentUser() ORDER BY created DESC"
+
+	searchAPIOffset = "offset"
+	searchAPIToken  = "token"
+
+	defaultRefreshInterval = "5m"
+	minRefreshInterval     = 30 * time.Second
+)
+
+// Config holds everything needed to talk to a Jira instance. Values are read
+// from the config file first, then overridden by environment variables and
+// finally by command line flags.
+type Config struct {
+	BaseURL  string `json:"baseUrl"`
+	Email    string `json:"email"`
+	APIToken string `json:"apiToken"`
+	AuthType string `json:"authType,omitempty"`
+
+	// JQL is the query the issue list starts with.
+	JQL string `json:"jql,omitempty"`
+
+	// MaxResults caps how many issues a search returns; 0 means no cap.
+	MaxResults int `json:"maxResults,omitempty"`
+	// SearchAPI selects the issue search flavour: "offset" for the classic
+	// /search endpoint (startAt/total) or "token" for /search/jql
+	// (nextPageToken). Defaults to "token" on Jira Cloud.
+	SearchAPI string `json:"searchApi,omitempty"`
+
+	// SavedQueries are shown as tabs after the starting query.
+	SavedQueries []SavedQuery `json:"savedQueries,omitempty"`
+	// ImportFavouriteFilters adds the user's favourite Jira filters as tabs.
+	ImportFavouriteFilters bool `json:"importFavouriteFilters,omitempty"`
+
+	// RefreshInterval is how often the issue list is fetched again, as a Go
+	// duration such as "5m"; "0" turns auto-refresh off.
+	RefreshInterval string `json:"refreshInterval,omitempty"`
+
+	// Notify picks the changes to your issues you are told about, and how.
+	Notify NotifyConfig `json:"notify,omitempty"`
+
+	path string   // file the config was loaded from
+	args []string // command line arguments after the flags: a command to run
+}
+
+// NotifyConfig configures notifications about changes other people make to
+// the issues assigned to or reported by you.
+type NotifyConfig struct {
+	// Events lists the changes to be told about: "comment", "transition"
+	// and "assign". There are no notifications unless set.
+	Events []string `json:"events,omitempty"`
+	// Method is "bell" (terminal bell and status bar, the default),
+	// "desktop" (notify-send) or "command".
+	Method string `json:"method,omitempty"`
+	// Command is run for every notification with the title and message as
+	// arguments, when Method is "command".
+	Command string `json:"command,omitempty"`
+}
+
+// SavedQuery is a named JQL query shown as a tab above the issue list.
+type SavedQuery struct {
+	Name string `json:"name"`
+	JQL  string `json:"jql"`
+	// Sort is the client-side sort of the tab, e.g. "priority desc, updated".
+	Sort string `json:"sort,omitempty"`
+}
+
+// appDataDir returns the directory for files the tool keeps between runs,
+// next to the default config file.
+func appDataDir() string {
+	return filepath.Dir(defaultConfigPath())
+}
+
+// appCacheDir returns the directory for data that can be fetched again from
+// Jira, such as cached issues.
+func appCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(appDataDir(), "cache")
+	}
+	return filepath.Join(dir, "jira-cli")
+}
+
+// defaultConfigPath returns $XDG_CONFIG_HOME/jira-cli/config.json (or the
+// platform equivalent).
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "config.json"
+	}
+	return filepath.Join(dir, "jira-cli", "config.json")
+}
+
+// LoadConfig builds the configuration from the config file, environment
+// variables and the given command line arguments, in increasing priority.
+// The arguments after the flags are kept as the command to run.
+func LoadConfig(args []string) (Config, error) {
+	fs := flag.NewFlagSet("jira", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: jira [flags] [command]\n\nWithout a command the interactive UI starts.\n\n%s\nFlags:\n", commandsUsage())
+		fs.PrintDefaults()
+	}
+	configPath := fs.String("config", "", "path to the config file (default "+defaultConfigPath()+")")
+	baseURL := fs.String("base-url", "", "Jira site URL, e.g. https://your-domain.atlassian.net")
+	email := fs.String("email", "", "account email (or username on Jira Server)")
+	apiToken := fs.String("token", "", "API token (or personal access token with --auth bearer)")
+	authType := fs.String("auth", "", "authentication type: basic or bearer")
+	jql := fs.String("jql", "", "JQL query to start with (default \""+defaultJQL+"\")")
+	maxResults := fs.Int("max-results", -1, "maximum number of issues to load, 0 for no limit")
+	refresh := fs.String("refresh", "", "auto-refresh interval, e.g. 5m, 0 to turn it off (default "+defaultRefreshInterval+")")
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	path := *configPath
+	if path == "" {
+		path = os.Getenv("JIRA_CONFIG")
+	}
+	if path == "" {
+		path = defaultConfigPath()
+	}
+
+	cfg, err := readConfigFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.path = path
+	cfg.args = fs.Args()
+
+	overrideFromEnv(&cfg.BaseURL, "JIRA_BASE_URL")
+	overrideFromEnv(&cfg.Email, "JIRA_EMAIL")
+	overrideFromEnv(&cfg.APIToken, "JIRA_API_TOKEN")
+	overrideFromEnv(&cfg.AuthType, "JIRA_AUTH_TYPE")
+	overrideFromEnv(&cfg.JQL, "JIRA_JQL")
+	overrideFromEnv(&cfg.RefreshInterval, "JIRA_REFRESH_INTERVAL")
+
+	overrideFromFlag(&cfg.BaseURL, *baseURL)
+	overrideFromFlag(&cfg.Email, *email)
+	overrideFromFlag(&cfg.APIToken, *apiToken)
+	overrideFromFlag(&cfg.AuthType, *authType)
+	overrideFromFlag(&cfg.JQL, *jql)
+	overrideFromFlag(&cfg.RefreshInterval, *refresh)
+	if v := os.Getenv("JIRA_MAX_RESULTS"); v != "" && *maxResults < 0 {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid JIRA_MAX_RESULTS %q: %w", v, err)
+		}
+		cfg.MaxResults = n
+	}
+	if *maxResults >= 0 {
+		cfg.MaxResults = *maxResults
+	}
+
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	cfg.AuthType = strings.ToLower(cfg.AuthType)
+	if cfg.AuthType == "" {
+		cfg.AuthType = authTypeBasic
+	}
+	if cfg.JQL == "" {
+		cfg.JQL = defaultJQL
+	}
+	if cfg.RefreshInterval == "" {
+		cfg.RefreshInterval = defaultRefreshInterval
+	}
+	if cfg.SearchAPI == "" {
+		cfg.SearchAPI = searchAPIOffset
+		if isCloudURL(cfg.BaseURL) {
+			cfg.SearchAPI = searchAPIToken
+		}
+	}
+
+	return cfg, cfg.validate()
+}
+
+func readConfigFile(path string) (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("error reading config file: %w", err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("error parsing config file %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// saveSavedQueries writes queries to the config file at path, leaving the
+// other settings in the file as they are.
+func saveSavedQueries(path string, queries []SavedQuery) error {
+	settings := map[string]json.RawMessage{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &settings); err != nil {
+			return fmt.Errorf("error parsing config file %s: %w", path, err)
+		}
+	}
+	encoded, err := json.Marshal(queries)
+	if err != nil {
+		return err
+	}
+	settings["savedQueries"] = encoded
+	data, err = json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("error writing config file: %w", err)
+	}
+	return nil
+}
+
+func overrideFromEnv(dst *string, name string) {
+	if v := os.Getenv(name); v != "" {
+		*dst = v
+	}
+}
+
+func overrideFromFlag(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
+
+func (cfg Config) validate() error {
+	if cfg.BaseURL == "" {
+		return errors.New("Jira base URL not set (use --base-url, JIRA_BASE_URL or baseUrl in the config file)")
+	}
+	if cfg.APIToken == "" {
+		return errors.New("Jira API token not set (use --token, JIRA_API_TOKEN or apiToken in the config file)")
+	}
+	switch cfg.AuthType {
+	case authTypeBasic:
+		if cfg.Email == "" {
+			return errors.New("Jira email not set (use --email, JIRA_EMAIL or email in the config file)")
+		}
+	case authTypeBearer:
+	default:
+		return fmt.Errorf("unknown auth type %q (expected %q or %q)", cfg.AuthType, authTypeBasic, authTypeBearer)
+	}
+	if cfg.SearchAPI != searchAPIOffset && cfg.SearchAPI != searchAPIToken {
+		return fmt.Errorf("unknown search API %q (expected %q or %q)", cfg.SearchAPI, searchAPIOffset, searchAPIToken)
+	}
+	if cfg.MaxResults < 0 {
+		return fmt.Errorf("maxResults must not be negative, got %d", cfg.MaxResults)
+	}
+	for _, event := range cfg.Notify.Events {
+		if event != eventComment && event != eventTransition && event != eventAssign {
+			return fmt.Errorf("unknown notification event %q (expected %q, %q or %q)", event, eventComment, eventTransition, eventAssign)
+		}
+	}
+	switch cfg.Notify.Method {
+	case "", notifyBell, notifyDesktop:
+	case notifyCommand:
+		if strings.TrimSpace(cfg.Notify.Command) == "" {
+			return errors.New("notify.command must be set when notify.method is \"command\"")
+		}
+	default:
+		return fmt.Errorf("unknown notification method %q (expected %q, %q or %q)", cfg.Notify.Method, notifyBell, notifyDesktop, notifyCommand)
+	}
+	interval, err := time.ParseDuration(cfg.RefreshInterval)
+	if err != nil {
+		return fmt.Errorf("invalid refresh interval %q: %w", cfg.RefreshInterval, err)
+	}
+	if interval != 0 && interval < minRefreshInterval {
+		return fmt.Errorf("refresh interval must be 0 or at least %s, got %s", minRefreshInterval, interval)
+	}
+	return nil
+}
+
+// refreshInterval returns how often to refresh the issue list, 0 for never.
+// It must only be called on a validated config.
+func (cfg Config) refreshInterval() time.Duration {
+	interval, _ := time.ParseDuration(cfg.RefreshInterval)
+	return interval
+}
+
+// isCloudURL reports whether rawURL points at a Jira Cloud site.
+func isCloudURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(u.Hostname(), ".atlassian.net")
+}
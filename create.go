@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// Fields of the create screen that the issue form handles itself rather than
+// through addFieldInputs.
+var createFormFields = map[string]bool{
+	"project":     true,
+	"issuetype":   true,
+	"summary":     true,
+	"description": true,
+	"priority":    true,
+	"assignee":    true,
+	"labels":      true,
+	"reporter":    true,
+}
+
+// showCreateIssueForm lets the user pick a project and issue type, then shows
+// the matching create screen. onCreated receives the new issue.
+func showCreateIssueForm(ctx context.Context, app *tview.Application, client *JiraClient, returnToMain func(), updateStatusFunc func(message string, isError bool), onCreated func(Issue)) {
+	go func() {
+		updateStatusFunc("Fetching projects...", false)
+		projects, err := client.FetchProjects(ctx)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				updateStatusFunc(fmt.Sprintf("Error fetching projects: %s", describeError(err)), true)
+				return
+			}
+			if len(projects) == 0 {
+				updateStatusFunc("No projects available.", true)
+				return
+			}
+			updateStatusFunc("Pick a project and issue type.", false)
+			showIssueTypeForm(ctx, app, client, projects, returnToMain, updateStatusFunc, onCreated)
+		})
+	}()
+}
+
+// showIssueTypeForm is the first step of issue creation: choosing where the
+// issue goes and what it is.
+func showIssueTypeForm(ctx context.Context, app *tview.Application, client *JiraClient, projects []Project, returnToMain func(), updateStatusFunc func(message string, isError bool), onCreated func(Issue)) {
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle("New Issue (Esc to cancel)")
+
+	var project Project
+	var issueTypes []CreateMetaIssueType
+	typeIndex := -1
+
+	typeDropDown := tview.NewDropDown().SetLabel("Issue Type")
+	setIssueTypes := func(types []CreateMetaIssueType) {
+		issueTypes = types
+		var options []string
+		for _, t := range types {
+			options = append(options, escapeTags(t.Name))
+		}
+		typeDropDown.SetOptions(options, func(option string, index int) {
+			typeIndex = index
+		})
+		typeDropDown.SetCurrentOption(0)
+	}
+
+	projectOptions := make([]string, len(projects))
+	for i, p := range projects {
+		projectOptions[i] = escapeTags(fmt.Sprintf("%s (%s)", p.Name, p.Key))
+	}
+	form.AddDropDown("Project", projectOptions, -1, func(option string, index int) {
+		if index < 0 {
+			return
+		}
+		project = projects[index]
+		setIssueTypes(nil)
+		go func() {
+			updateStatusFunc(fmt.Sprintf("Fetching issue types for %s...", project.Key), false)
+			types, err := client.FetchCreateMeta(ctx, project.Key)
+			app.QueueUpdateDraw(func() {
+				if err != nil {
+					updateStatusFunc(fmt.Sprintf("Error fetching issue types: %s", describeError(err)), true)
+					return
+				}
+				if projects[index].Key != project.Key {
+					return // the user picked another project in the meantime
+				}
+				updateStatusFunc(fmt.Sprintf("Loaded %d issue types for %s.", len(types), project.Key), false)
+				setIssueTypes(types)
+			})
+		}()
+	})
+	form.AddFormItem(typeDropDown)
+
+	form.AddButton("Next", func() {
+		if typeIndex < 0 || typeIndex >= len(issueTypes) {
+			updateStatusFunc("Pick a project and an issue type first.", true)
+			return
+		}
+		showIssueFieldsForm(ctx, app, client, project, issueTypes[typeIndex], returnToMain, updateStatusFunc, onCreated)
+	})
+	form.AddButton("Cancel", returnToMain)
+	form.SetCancelFunc(returnToMain)
+
+	app.SetRoot(centered(form, 70, 9), true).SetFocus(form)
+}
+
+// showIssueFieldsForm is the second step of issue creation: the create screen
+// of the chosen project and issue type.
+func showIssueFieldsForm(ctx context.Context, app *tview.Application, client *JiraClient, project Project, issueType CreateMetaIssueType, returnToMain func(), updateStatusFunc func(message string, isError bool), onCreated func(Issue)) {
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle(fmt.Sprintf("New %s in %s (Esc to cancel)", escapeTags(issueType.Name), escapeTags(project.Key)))
+
+	var summary, description, labels string
+	var assignee *User
+	priority := -1
+
+	form.AddInputField("Summary *", "", 0, nil, func(text string) {
+		summary = text
+	})
+	form.AddTextArea("Description", "", 0, 6, 0, func(text string) {
+		description = text
+	})
+	height := 2 + 7
+
+	priorityMeta, hasPriority := issueType.Fields["priority"]
+	if hasPriority && len(priorityMeta.AllowedValues) > 0 {
+		options := []string{"(default)"}
+		for _, v := range priorityMeta.AllowedValues {
+			options = append(options, escapeTags(v.Label()))
+		}
+		form.AddDropDown("Priority", options, 0, func(option string, index int) {
+			priority = index - 1
+		})
+		height += 2
+	}
+
+	_, hasAssignee := issueType.Fields["assignee"]
+	if hasAssignee {
+		form.AddTextView("Assignee", "Unassigned", 0, 1, false, false)
+		height += 2
+	}
+	if _, ok := issueType.Fields["labels"]; ok {
+		form.AddInputField("Labels", "", 0, nil, func(text string) {
+			labels = text
+		})
+		height += 2
+	}
+
+	// Remaining required fields, plus optional ones with a fixed set of values
+	// (components, fix versions, custom selects...).
+	itemsBefore := form.GetFormItemCount()
+	inputs := addFieldInputs(form, issueType.Fields, client.isCloud(), func(key string, meta FieldMeta) bool {
+		if createFormFields[key] {
+			return false
+		}
+		return (meta.Required && !meta.HasDefaultValue) || len(meta.AllowedValues) > 0
+	})
+	height += (form.GetFormItemCount() - itemsBefore) * 2
+
+	root := centered(form, 80, min(height+4, 40))
+	backToForm := func() {
+		app.SetRoot(root, true).SetFocus(form)
+	}
+
+	form.AddButton("Create", func() {
+		if strings.TrimSpace(summary) == "" {
+			updateStatusFunc("Summary is required.", true)
+			return
+		}
+		fields, err := inputs.values()
+		if err != nil {
+			updateStatusFunc(err.Error(), true)
+			return
+		}
+		fields["project"] = map[string]string{"key": project.Key}
+		fields["issuetype"] = map[string]string{"id": issueType.ID}
+		fields["summary"] = strings.TrimSpace(summary)
+		if strings.TrimSpace(description) != "" {
+			fields["description"] = description
+		}
+		if priority >= 0 {
+			fields["priority"] = map[string]string{"id": priorityMeta.AllowedValues[priority].ID}
+		}
+		if assignee != nil {
+			fields["assignee"] = assignee.Ref()
+		}
+		if labelList := strings.Fields(strings.ReplaceAll(labels, ",", " ")); len(labelList) > 0 {
+			fields["labels"] = labelList
+		}
+
+		returnToMain()
+		go func() {
+			updateStatusFunc(fmt.Sprintf("Creating issue in %s...", project.Key), false)
+			key, err := client.CreateIssue(ctx, fields)
+			if err != nil {
+				updateStatusFunc(fmt.Sprintf("Error creating issue: %s", describeError(err)), true)
+				return
+			}
+			issue, err := client.FetchIssue(ctx, key)
+			app.QueueUpdateDraw(func() {
+				if err != nil {
+					updateStatusFunc(fmt.Sprintf("Created %s, but could not load it: %s", key, describeError(err)), true)
+					return
+				}
+				onCreated(issue)
+				updateStatusFunc(fmt.Sprintf("Created %s.", key), false)
+			})
+		}()
+	})
+	if hasAssignee {
+		form.AddButton("Pick Assignee", func() {
+			go func() {
+				updateStatusFunc("Fetching assignable users...", false)
+				users, err := client.FetchAssignableUsers(ctx, project.Key, "", "")
+				app.QueueUpdateDraw(func() {
+					if err != nil {
+						updateStatusFunc(fmt.Sprintf("Error fetching users: %s", describeError(err)), true)
+						return
+					}
+					updateStatusFunc(fmt.Sprintf("Loaded %d users.", len(users)), false)
+					showUserPicker(app, "Assignee (Esc to go back)", users, nil, func(u User) {
+						assignee = &u
+						if item, ok := form.GetFormItemByLabel("Assignee").(*tview.TextView); ok {
+							item.SetText(u.DisplayName)
+						}
+						backToForm()
+					}, backToForm)
+				})
+			}()
+		})
+	}
+	form.AddButton("Cancel", returnToMain)
+	form.SetCancelFunc(returnToMain)
+
+	backToForm()
+}
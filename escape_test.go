@@ -0,0 +1,289 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// nastySummaries look like tview style, region or hyperlink tags, or break
+// naive escaping.
+var nastySummaries = []string{
+	"[red]x[-]",
+	"[::b]",
+	"[[x]]",
+	"foo]",
+	"[#ff0000]",
+	"[yellow]",
+	"[-:-:-]",
+	"[x] todo [ ]",
+	"[::bu:http://evil]click[:::-]",
+	`["region"]x[""]`,
+	"a[]b",
+	"ends with [",
+	"[red[blue]]",
+	"][",
+	"100% [done]",
+}
+
+// nastyWiki are wiki markup texts that Jira shows as they are.
+var nastyWiki = []string{
+	"[red]x[-]",
+	"[::b]",
+	"[[x]]",
+	"foo]",
+	"[yellow]",
+	"[red]alert[-] and [bar|baz]",
+	"[x] todo [ ]",
+	"[::bu]click[::-]",
+	"[::bu:http://evil]click",
+	"[red:blue:b:https://evil[]x",
+}
+
+// renderTagged draws text the way the UI's views do and returns the lines
+// shown, without trailing spaces, and the style of each of their runes.
+func renderTagged(t *testing.T, text string) ([]string, [][]tcell.Style) {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer screen.Fini()
+	const width, height = 300, 60
+	screen.SetSize(width, height)
+
+	view := tview.NewTextView().SetDynamicColors(true).SetWrap(false)
+	view.SetRect(0, 0, width, height)
+	view.SetText(text)
+	view.Draw(screen)
+	screen.Show()
+
+	cells, _, _ := screen.GetContents()
+	var lines []string
+	var styles [][]tcell.Style
+	for y := 0; y < height; y++ {
+		var line []rune
+		var lineStyles []tcell.Style
+		for _, cell := range cells[y*width : (y+1)*width] {
+			r := ' '
+			if len(cell.Runes) > 0 {
+				r = cell.Runes[0]
+			}
+			line = append(line, r)
+			lineStyles = append(lineStyles, cell.Style)
+		}
+		n := len(strings.TrimRight(string(line), " ")) // ASCII padding
+		n = len([]rune(string(line)[:n]))
+		lines = append(lines, string(line[:n]))
+		styles = append(styles, lineStyles[:n])
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines, styles = lines[:len(lines)-1], styles[:len(styles)-1]
+	}
+	return lines, styles
+}
+
+// plainStyle reports whether style has no attributes and no hyperlink.
+func plainStyle(style tcell.Style) bool {
+	_, _, attrs := style.Decompose()
+	return attrs == 0 && style == style.Url("")
+}
+
+// findLiteral returns the line and rune index where want is shown in one
+// style, failing the test if it is not shown as is.
+func findLiteral(t *testing.T, text, want string) (line, start int, style tcell.Style) {
+	t.Helper()
+	lines, styles := renderTagged(t, text)
+	for y, shown := range lines {
+		i := strings.Index(shown, want)
+		if i < 0 {
+			continue
+		}
+		start = len([]rune(shown[:i]))
+		style = styles[y][start]
+		for x := start; x < start+len([]rune(want)); x++ {
+			if styles[y][x] != style {
+				t.Errorf("%q: the style changes at rune %d of %q in %q", text, x-start, want, shown)
+				break
+			}
+		}
+		return y, start, style
+	}
+	t.Errorf("%q is shown as %q, want it to contain %q", text, lines, want)
+	return -1, 0, style
+}
+
+func TestFormatIssueRowEscapes(t *testing.T) {
+	for _, summary := range nastySummaries {
+		issue := Issue{Key: "TEST-1", Fields: Fields{Summary: summary, Status: Status{Name: "To Do"}}}
+		for _, match := range []issueMatch{
+			{},
+			{key: []int{0, 1}, summary: []int{0, 1, 2}},
+			{summary: []int{1, 3, 4, 7, 8, 9}},
+			{summary: []int{len([]rune(summary)) - 1}},
+		} {
+			lines, styles := renderTagged(t, formatIssueRow(issue, match, 0))
+			want := "TEST-1: " + summary
+			if len(lines) != 1 || lines[0] != want {
+				t.Errorf("row of %q (match %v) is shown as %q, want %q", summary, match.summary, lines, want)
+				continue
+			}
+			base := styles[0][len("TEST-1")] // the colon
+			if fg, _, attrs := base.Decompose(); fg != tcell.ColorRed || attrs != 0 || base != base.Url("") {
+				t.Errorf("row of %q is styled %v", summary, base)
+			}
+			matched := map[int]bool{}
+			for _, i := range match.summary {
+				matched[i] = true
+			}
+			for i := range []rune(summary) {
+				var want tcell.AttrMask
+				if matched[i] {
+					want = tcell.AttrBold | tcell.AttrUnderline
+				}
+				got := styles[0][len("TEST-1: ")+i]
+				if fg, _, attrs := got.Decompose(); fg != tcell.ColorRed || attrs != want || got != got.Url("") {
+					t.Errorf("rune %d of %q (match %v) is styled %v, want attributes %v", i, summary, match.summary, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatIssueDetailsEscapes(t *testing.T) {
+	for i, summary := range nastySummaries {
+		description := nastyWiki[i%len(nastyWiki)]
+		issue := Issue{Key: "TEST-1", Fields: Fields{
+			Summary:     summary,
+			Status:      Status{Name: summary},
+			IssueType:   IssueType{Name: summary},
+			Assignee:    &User{DisplayName: summary},
+			Description: description,
+			Created:     CustomTime{time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)},
+			Updated:     CustomTime{time.Date(2026, 1, 3, 3, 4, 0, 0, time.UTC)},
+		}}
+		lines, styles := renderTagged(t, formatIssueDetails(issue))
+		want := []string{
+			"Key: TEST-1",
+			"Summary: " + summary,
+			"Status: " + summary,
+			"Issue Type: " + summary,
+			"Assignee: " + summary,
+			"Created: 2026-01-02 03:04",
+			"Updated: 2026-01-03 03:04",
+			"",
+			"Description:",
+			description,
+		}
+		if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+			t.Errorf("details of %q are shown as %q, want %q", summary, lines, want)
+			continue
+		}
+		label := styles[0][0]
+		for y, line := range want[:7] {
+			colon := strings.Index(line, ":")
+			for x, style := range styles[y] {
+				if x <= colon && style != label {
+					t.Errorf("details of %q: rune %d of %q is styled %v, want %v", summary, x, line, style, label)
+					break
+				}
+				if x > colon+1 && (style != styles[y][colon+2] || !plainStyle(style)) {
+					t.Errorf("details of %q: rune %d of %q is styled %v", summary, x, line, style)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestRenderRichTextEscapes(t *testing.T) {
+	for _, text := range nastyWiki {
+		_, _, style := findLiteral(t, renderRichText(text), text)
+		if style != tcell.StyleDefault.Foreground(tview.Styles.PrimaryTextColor).Background(tview.Styles.PrimitiveBackgroundColor) {
+			t.Errorf("%q is styled %v", text, style)
+		}
+	}
+
+	heading := renderRichText("h2. [yellow]T [red]x[-]")
+	if _, _, style := findLiteral(t, heading, "[yellow]T [red]x[-]"); style != style.Url("") {
+		t.Errorf("heading is styled %v", style)
+	}
+
+	code := renderRichText("{code}\n[yellow]\n[red]x[-] [::b]\n{code}\nafter")
+	_, _, first := findLiteral(t, code, "[yellow]")
+	_, _, second := findLiteral(t, code, "[red]x[-] [::b]")
+	if fg, _, _ := first.Decompose(); fg != tcell.ColorLightGreen || second != first {
+		t.Errorf("code is styled %v and %v", first, second)
+	}
+	if _, _, after := findLiteral(t, code, "after"); after == first {
+		t.Errorf("the code style leaks after the block")
+	}
+
+	doc := map[string]interface{}{"type": "doc", "content": []interface{}{
+		map[string]interface{}{"type": "paragraph", "content": []interface{}{
+			map[string]interface{}{"type": "text", "text": "[red]x[-]", "marks": []interface{}{
+				map[string]interface{}{"type": "textColor", "attrs": map[string]interface{}{"color": "red::bu:http://evil"}},
+			}},
+			map[string]interface{}{"type": "text", "text": " [::b]", "marks": []interface{}{
+				map[string]interface{}{"type": "textColor", "attrs": map[string]interface{}{"color": "#ff0000"}},
+			}},
+		}},
+	}}
+	rendered := renderRichText(doc)
+	_, _, plain := findLiteral(t, rendered, "[red]x[-]")
+	if _, _, attrs := plain.Decompose(); attrs != 0 || plain != plain.Url("") {
+		t.Errorf("an invalid ADF colour is styled %v", plain)
+	}
+	if _, _, red := findLiteral(t, rendered, " [::b]"); red != red.Url("") || red == plain {
+		t.Errorf("a hex ADF colour is styled %v", red)
+	}
+}
+
+func TestRenderCommentsEscapes(t *testing.T) {
+	for _, text := range nastyWiki {
+		issue := Issue{Key: "TEST-1", Fields: Fields{Comments: &Comments{Comments: []Comment{{
+			ID:      "1",
+			Author:  &User{DisplayName: text},
+			Body:    text,
+			Created: CustomTime{time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)},
+		}}}}}
+		list := tview.NewList()
+		renderComments(list, issue, func(Comment) bool { return false })
+
+		header, body := list.GetItemText(0)
+		_, start, author := findLiteral(t, header, text+" ")
+		_, at, date := findLiteral(t, header, "2026-01-02 03:04")
+		if at != start+len([]rune(text))+1 || !plainStyle(author) || !plainStyle(date) || author == date {
+			t.Errorf("comment header %q is styled %v and %v", header, author, date)
+		}
+		if _, _, style := findLiteral(t, body, text); !plainStyle(style) {
+			t.Errorf("comment %q is styled %v", text, style)
+		}
+	}
+}
+
+func TestQueryTitleEscapes(t *testing.T) {
+	for _, text := range nastySummaries {
+		query := issueQuery{sprint: &Sprint{Name: text, Goal: text}}
+		findLiteral(t, query.title(), text+" | Goal: "+text)
+	}
+}
+
+func TestStatusTextEscapes(t *testing.T) {
+	for _, text := range nastySummaries {
+		for _, isError := range []bool{false, true} {
+			message := "Error fetching tickets: " + text
+			_, _, style := findLiteral(t, statusText(message, isError), message)
+			want := tcell.ColorGreen
+			if isError {
+				want = tcell.ColorRed
+			}
+			if fg, _, attrs := style.Decompose(); fg != want || attrs != 0 || style != style.Url("") {
+				t.Errorf("status %q is styled %v", message, style)
+			}
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const unassignedFilterValue = "Unassigned"
+
+// filterFields lists the fields the filter panel offers, in column order.
+var filterFields = []struct {
+	key   string
+	label string
+}{
+	{"status", "Status"},
+	{"issueType", "Type"},
+	{"assignee", "Assignee"},
+	{"priority", "Priority"},
+	{"labels", "Labels"},
+}
+
+// issueFilters holds the values selected per filter field. An issue is shown
+// if, for every field with a selection, it has one of the selected values.
+type issueFilters map[string]map[string]bool
+
+// filterValues returns the values an issue has for a filter field.
+func filterValues(issue Issue, key string) []string {
+	switch key {
+	case "status":
+		return []string{issue.Fields.Status.Name}
+	case "issueType":
+		return []string{issue.Fields.IssueType.Name}
+	case "assignee":
+		if issue.Fields.Assignee == nil {
+			return []string{unassignedFilterValue}
+		}
+		return []string{issue.Fields.Assignee.DisplayName}
+	case "priority":
+		if issue.Fields.Priority == nil {
+			return nil
+		}
+		return []string{issue.Fields.Priority.Name}
+	case "labels":
+		return issue.Fields.Labels
+	}
+	return nil
+}
+
+func (f issueFilters) matches(issue Issue) bool {
+	for key, selected := range f {
+		if len(selected) == 0 {
+			continue
+		}
+		found := false
+		for _, v := range filterValues(issue, key) {
+			if selected[v] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// chips renders the active filters for the list title.
+func (f issueFilters) chips() string {
+	var chips strings.Builder
+	for _, field := range filterFields {
+		selected := f[field.key]
+		if len(selected) == 0 {
+			continue
+		}
+		values := make([]string, 0, len(selected))
+		for v := range selected {
+			values = append(values, v)
+		}
+		sort.Strings(values)
+		fmt.Fprintf(&chips, " [black:yellow] %s: %s [-:-]", field.label, escapeTags(truncate(strings.Join(values, ", "), 40)))
+	}
+	return chips.String()
+}
+
+// filterSources caches the statuses and users offered by the filter panel
+// besides the values found in the loaded issues.
+type filterSources struct {
+	loaded   bool
+	statuses []string
+	users    []string
+}
+
+// load fetches the statuses and users once per session. done is called on the
+// UI goroutine when the fetch succeeded.
+func (s *filterSources) load(ctx context.Context, app *tview.Application, client *JiraClient, updateStatusFunc func(message string, isError bool), done func()) {
+	if s.loaded {
+		return
+	}
+	s.loaded = true
+	go func() {
+		statuses, err := client.FetchJiraStatuses(ctx)
+		if err != nil {
+			app.QueueUpdateDraw(func() {
+				s.loaded = false
+				updateStatusFunc(fmt.Sprintf("Error fetching statuses: %s", describeError(err)), true)
+			})
+			return
+		}
+		users, err := client.FetchJiraUsers(ctx)
+		app.QueueUpdateDraw(func() {
+			for _, st := range statuses {
+				s.statuses = append(s.statuses, st.Name)
+			}
+			if err != nil {
+				updateStatusFunc(fmt.Sprintf("Error fetching users: %s", describeError(err)), true)
+			}
+			for _, u := range users {
+				s.users = append(s.users, u.DisplayName)
+			}
+			done()
+		})
+	}()
+}
+
+// filterOptions returns the values offered for a filter field with how many
+// of issues have each. Values found in the issues come first.
+func filterOptions(key string, issues []Issue, sources *filterSources, filters issueFilters) ([]string, map[string]int) {
+	counts := map[string]int{}
+	for _, issue := range issues {
+		for _, v := range filterValues(issue, key) {
+			counts[v]++
+		}
+	}
+	known := map[string]bool{}
+	for v := range counts {
+		known[v] = true
+	}
+	for v := range filters[key] {
+		known[v] = true
+	}
+	var extra []string
+	switch key {
+	case "status":
+		extra = sources.statuses
+	case "assignee":
+		extra = append([]string{unassignedFilterValue}, sources.users...)
+	}
+	for _, v := range extra {
+		known[v] = true
+	}
+	delete(known, "")
+
+	values := make([]string, 0, len(known))
+	for v := range known {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool {
+		a, b := values[i], values[j]
+		if (counts[a] > 0) != (counts[b] > 0) {
+			return counts[a] > 0
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	return values, counts
+}
+
+// showFilterPanel shows a checklist per filter field. Toggling a value updates
+// filters and calls onChange straight away.
+func showFilterPanel(ctx context.Context, app *tview.Application, client *JiraClient, issues []Issue, filters issueFilters, sources *filterSources, returnToMain func(), updateStatusFunc func(message string, isError bool), onChange func()) {
+	layout := tview.NewFlex()
+	columns := make([]*tview.List, len(filterFields))
+	values := make([][]string, len(filterFields))
+	focused := 0
+
+	render := func(col int) {
+		field := filterFields[col]
+		list := columns[col]
+		current := list.GetCurrentItem()
+		options, counts := filterOptions(field.key, issues, sources, filters)
+		values[col] = options
+		list.Clear()
+		for _, v := range options {
+			mark := "  "
+			if filters[field.key][v] {
+				mark = "[green]✓[-] "
+			}
+			text := mark + escapeTags(v)
+			if counts[v] > 0 {
+				text += fmt.Sprintf(" [gray](%d)", counts[v])
+			}
+			list.AddItem(text, "", 0, nil)
+		}
+		if len(options) == 0 {
+			list.AddItem("[gray]No values", "", 0, nil)
+		}
+		list.SetCurrentItem(current)
+		title := field.label
+		if n := len(filters[field.key]); n > 0 {
+			title += fmt.Sprintf(" (%d)", n)
+		}
+		list.SetTitle(title)
+	}
+	renderAll := func() {
+		for col := range columns {
+			render(col)
+		}
+	}
+
+	toggle := func(col, index int) {
+		if index < 0 || index >= len(values[col]) {
+			return
+		}
+		key, v := filterFields[col].key, values[col][index]
+		if filters[key] == nil {
+			filters[key] = map[string]bool{}
+		}
+		if filters[key][v] {
+			delete(filters[key], v)
+		} else {
+			filters[key][v] = true
+		}
+		render(col)
+		onChange()
+	}
+	clearColumn := func(col int) {
+		delete(filters, filterFields[col].key)
+		render(col)
+		onChange()
+	}
+	focusColumn := func(col int) {
+		focused = (col + len(columns)) % len(columns)
+		app.SetFocus(columns[focused])
+	}
+
+	for col := range filterFields {
+		col := col
+		list := tview.NewList().ShowSecondaryText(false)
+		list.SetBorder(true)
+		list.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+		list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+			toggle(col, index)
+		})
+		list.SetDoneFunc(returnToMain)
+		list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			switch {
+			case event.Key() == tcell.KeyTab || event.Key() == tcell.KeyRight || event.Rune() == 'l':
+				focusColumn(col + 1)
+			case event.Key() == tcell.KeyBacktab || event.Key() == tcell.KeyLeft || event.Rune() == 'h':
+				focusColumn(col - 1)
+			case event.Rune() == ' ':
+				toggle(col, list.GetCurrentItem())
+			case event.Rune() == 'c':
+				clearColumn(col)
+			case event.Rune() == 'C':
+				for i := range columns {
+					delete(filters, filterFields[i].key)
+				}
+				renderAll()
+				onChange()
+			case event.Rune() == 'f' || event.Rune() == 'q':
+				returnToMain()
+			default:
+				return event
+			}
+			return nil
+		})
+		columns[col] = list
+		layout.AddItem(list, 0, 1, col == 0)
+	}
+	renderAll()
+
+	help := tview.NewTextView().SetDynamicColors(true).
+		SetText("[gray]Space/Enter toggle · Tab/←/→ switch column · c clear column · C clear all · Esc close")
+	panel := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(layout, 0, 1, true).
+		AddItem(help, 1, 0, false)
+	panel.SetBorder(true).SetTitle("Filters")
+
+	sources.load(ctx, app, client, updateStatusFunc, renderAll)
+	app.SetRoot(centered(panel, 120, 30), true)
+	focusColumn(focused)
+}
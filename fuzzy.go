@@ -0,0 +1,185 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// fuzzyMatch reports whether the runes of pattern appear in text in order,
+// ignoring case, and scores how good the match is: a contiguous substring
+// beats scattered runes, and runes at the start of words or right after the
+// previous match score extra. positions are the rune indexes of text that
+// matched, for highlighting.
+func fuzzyMatch(pattern, text string) (score int, positions []int, ok bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+	p := lowerRunes(pattern)
+	t := lowerRunes(text)
+
+	// A plain substring match is the best we can do.
+	if start := runeIndex(t, p); start >= 0 {
+		for i := range p {
+			positions = append(positions, start+i)
+		}
+		score = 100 + 10*len(p)
+		if start == 0 || isWordBoundary(t, start) {
+			score += 20
+		}
+		return score - start, positions, true
+	}
+
+	pi := 0
+	last := -2
+	for ti := 0; ti < len(t) && pi < len(p); ti++ {
+		if t[ti] != p[pi] {
+			continue
+		}
+		score += 1
+		if ti == last+1 {
+			score += 5
+		}
+		if isWordBoundary(t, ti) {
+			score += 8
+		}
+		positions = append(positions, ti)
+		last = ti
+		pi++
+	}
+	if pi < len(p) {
+		return 0, nil, false
+	}
+	return score, positions, true
+}
+
+// lowerRunes returns the runes of s in lower case, one for each rune of s, so
+// that the positions found in it are those of s for highlighting.
+func lowerRunes(s string) []rune {
+	runes := []rune(s)
+	for i, r := range runes {
+		runes[i] = unicode.ToLower(r)
+	}
+	return runes
+}
+
+// runeIndex returns the index of the first instance of sub in s, or -1.
+func runeIndex(s, sub []rune) int {
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if slices.Equal(s[i:i+len(sub)], sub) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isWordBoundary(t []rune, i int) bool {
+	return i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1])
+}
+
+// typoMatch is the typo-tolerant fallback for fuzzyMatch: it finds the word of
+// text, or a word prefix, closest to pattern within a small edit distance
+// (one typo for short patterns, two from eight runes on). positions are the
+// rune indexes of the matched word.
+func typoMatch(pattern, text string) (score int, positions []int, ok bool) {
+	p := lowerRunes(pattern)
+	if len(p) < 4 {
+		return 0, nil, false
+	}
+	maxTypos := 1
+	if len(p) >= 8 {
+		maxTypos = 2
+	}
+	t := lowerRunes(text)
+	best := maxTypos + 1
+	for start := 0; start < len(t); start++ {
+		if !isWordBoundary(t, start) || !unicode.IsLetter(t[start]) && !unicode.IsDigit(t[start]) {
+			continue
+		}
+		end := start
+		for end < len(t) && (unicode.IsLetter(t[end]) || unicode.IsDigit(t[end])) {
+			end++
+		}
+		word := t[start:end]
+		// Compare with the whole word and with the prefix the pattern could
+		// be the beginning of.
+		candidates := [][]rune{word}
+		if len(word) > len(p) {
+			candidates = append(candidates, word[:len(p)])
+		}
+		for _, c := range candidates {
+			if d := editDistance(p, c); d < best {
+				best = d
+				positions = positions[:0]
+				for i := start; i < start+len(c); i++ {
+					positions = append(positions, i)
+				}
+			}
+		}
+	}
+	if best > maxTypos {
+		return 0, nil, false
+	}
+	return 50 - 15*best, positions, true
+}
+
+// editDistance is the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b.
+func editDistance(a, b []rune) int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(a)][len(b)]
+}
+
+// highlightMatches renders text with the runes at positions underlined and
+// bold, escaping colour tags. The surrounding colour is kept.
+func highlightMatches(text string, positions []int) string {
+	if len(positions) == 0 {
+		return escapeTags(text)
+	}
+	matched := map[int]bool{}
+	for _, p := range positions {
+		matched[p] = true
+	}
+	var b strings.Builder
+	var run []rune
+	inMatch := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if inMatch {
+			b.WriteString("[::bu]" + escapeTags(string(run)) + "[::-]")
+		} else {
+			b.WriteString(escapeTags(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(text) {
+		if matched[i] != inMatch {
+			flush()
+			inMatch = matched[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
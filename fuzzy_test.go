@@ -0,0 +1,83 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		positions     []int
+		ok            bool
+	}{
+		{"", "anything", nil, true},
+		{"log", "Fix Login page", []int{4, 5, 6}, true},
+		{"LOG", "fix login page", []int{4, 5, 6}, true},
+		{"flp", "Fix Login page", []int{0, 4, 10}, true},
+		{"xyz", "Fix Login page", nil, false},
+		// Positions are those of the runes of the text, whatever their size.
+		{"café", "Ünïcode café", []int{8, 9, 10, 11}, true},
+		{"stan", "İSTANBUL office", []int{1, 2, 3, 4}, true},
+		{"ok", "\xff\xfe ok", []int{3, 4}, true},
+	}
+	for _, tt := range tests {
+		_, positions, ok := fuzzyMatch(tt.pattern, tt.text)
+		if ok != tt.ok || !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, %v, want %v, %v", tt.pattern, tt.text, positions, ok, tt.positions, tt.ok)
+		}
+	}
+}
+
+func TestFuzzyMatchScore(t *testing.T) {
+	// Better matches first.
+	texts := []string{"login", "fix login", "the blogging tool", "large old gate"}
+	last := 1 << 30
+	for _, text := range texts {
+		score, _, ok := fuzzyMatch("log", text)
+		if !ok || score >= last {
+			t.Errorf("fuzzyMatch(log, %q) = %d, %v, want a match scoring under %d", text, score, ok, last)
+		}
+		last = score
+	}
+}
+
+func TestTypoMatch(t *testing.T) {
+	tests := []struct {
+		pattern, text string
+		positions     []int
+		ok            bool
+	}{
+		{"prodcution", "Broken production deploy", []int{7, 8, 9, 10, 11, 12, 13, 14, 15, 16}, true},
+		{"deplyo", "Broken production deploy", []int{18, 19, 20, 21, 22, 23}, true},
+		{"confg", "Configuration screen", []int{0, 1, 2, 3, 4}, true},
+		{"über", "Das Übel", []int{4, 5, 6, 7}, true},
+		{"bug", "A bag", nil, false},           // too short for typos
+		{"deploy", "Broken delay", nil, false}, // two typos in six runes
+		{"configurtaoin", "configuration", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, true},
+	}
+	for _, tt := range tests {
+		_, positions, ok := typoMatch(tt.pattern, tt.text)
+		if ok != tt.ok || !reflect.DeepEqual(positions, tt.positions) {
+			t.Errorf("typoMatch(%q, %q) = %v, %v, want %v, %v", tt.pattern, tt.text, positions, ok, tt.positions, tt.ok)
+		}
+	}
+}
+
+func TestHighlightMatches(t *testing.T) {
+	tests := []struct {
+		text      string
+		positions []int
+		want      string
+	}{
+		{"plain [text]", nil, "plain [[::]text]"},
+		{"Fix login", []int{4, 5, 6}, "Fix [::bu]log[::-]in"},
+		{"Ünïcode café", []int{8, 9, 10, 11}, "Ünïcode [::bu]café[::-]"},
+		{"[a] b", []int{0, 1, 2}, "[::bu][[::]a][::-] b"},
+	}
+	for _, tt := range tests {
+		if got := highlightMatches(tt.text, tt.positions); got != tt.want {
+			t.Errorf("highlightMatches(%q, %v) = %q, want %q", tt.text, tt.positions, got, tt.want)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
@@ -56,8 +57,78 @@ func GenerateBranchName(issue Issue) string {
 	)
 	sanitizedSummary = reg.Replace(sanitizedSummary)
 	// Limit length to avoid overly long branch names
-	if len(sanitizedSummary) > 60 {
-		sanitizedSummary = sanitizedSummary[:100]
+	if runes := []rune(sanitizedSummary); len(runes) > 60 {
+		sanitizedSummary = strings.TrimRight(string(runes[:60]), "-")
 	}
 	return fmt.Sprintf("feature/%s-%s", issue.Key, sanitizedSummary)
 }
+
+// centered wraps p in a layout that keeps it in the middle of the screen.
+func centered(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 1, true).
+			AddItem(nil, 0, 1, false), width, 1, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// EditInEditor suspends the TUI, opens text in $VISUAL / $EDITOR and returns
+// the edited text.
+func EditInEditor(app *tview.Application, text string) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", "jira-*.txt")
+	if err != nil {
+		return text, fmt.Errorf("error creating temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(text); err != nil {
+		file.Close()
+		return text, fmt.Errorf("error writing temporary file: %w", err)
+	}
+	file.Close()
+
+	// $EDITOR may contain arguments, e.g. "code --wait".
+	args := append(strings.Fields(editor), file.Name())
+	var runErr error
+	app.Suspend(func() {
+		cmd := exec.Command(args[0], args[1:]...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		runErr = cmd.Run()
+	})
+	if runErr != nil {
+		return text, fmt.Errorf("error running %s: %w", args[0], runErr)
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return text, fmt.Errorf("error reading temporary file: %w", err)
+	}
+	return strings.TrimRight(string(edited), "\n"), nil
+}
+
+// truncate shortens s to at most n runes, adding an ellipsis when cut.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
+
+// escapeTags escapes text for views with dynamic colours so that it is shown
+// as is. tview.Escape is not enough: the URL of a hyperlink tag such as
+// "[::u:https://example.com]" may contain any character, including the "["
+// it relies on. Instead every "[" is followed by the empty tag "[::]", which
+// keeps it from opening a tag and changes nothing itself.
+func escapeTags(text string) string {
+	return strings.ReplaceAll(text, "[", "[[::]")
+}
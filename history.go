@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const maxJQLHistory = 100
+
+// jqlHistory holds the JQL queries run from the command bar, oldest first.
+// It is persisted as one query per line.
+type jqlHistory struct {
+	path    string
+	entries []string
+}
+
+func loadJQLHistory() *jqlHistory {
+	h := &jqlHistory{path: filepath.Join(appDataDir(), "jql_history")}
+	data, err := os.ReadFile(h.path)
+	if err != nil {
+		return h
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	return h
+}
+
+// add records jql as the most recent query, dropping older duplicates, and
+// saves the history.
+func (h *jqlHistory) add(jql string) error {
+	jql = strings.Join(strings.Fields(jql), " ")
+	if jql == "" {
+		return nil
+	}
+	entries := h.entries[:0:0]
+	for _, e := range h.entries {
+		if e != jql {
+			entries = append(entries, e)
+		}
+	}
+	entries = append(entries, jql)
+	if len(entries) > maxJQLHistory {
+		entries = entries[len(entries)-maxJQLHistory:]
+	}
+	h.entries = entries
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0o600)
+}
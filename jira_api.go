@@ -1,24 +1,29 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"io"
-	"net/http"
 	"net/url"
 	"time"
 )
 
 // --- Jira API Configuration ---
 const (
-	jiraBaseURL = "https://vus-edtech.atlassian.net"
-	jiraAPIPath = "/rest/api/2/search"
+	jiraAPIPath      = "/rest/api/2/search"
+	jiraTokenAPIPath = "/rest/api/2/search/jql"
+
+	issueFields = "summary,status,issuetype,assignee,reporter,priority,labels,duedate,description,created,updated,comment"
 )
 
 // --- Jira Data Structures ---
 
 type JiraSearchResponse struct {
-	Issues []Issue `json:"issues"`
+	StartAt       int     `json:"startAt"`
+	MaxResults    int     `json:"maxResults"`
+	Total         int     `json:"total"`
+	NextPageToken string  `json:"nextPageToken"`
+	IsLast        bool    `json:"isLast"`
+	Issues        []Issue `json:"issues"`
 }
 
 type Issue struct {
@@ -34,6 +39,8 @@ type Fields struct {
 	Assignee    *User       `json:"assignee"`
 	Reporter    *User       `json:"reporter"`
 	Priority    *Priority   `json:"priority"`
+	Labels      []string    `json:"labels"`
+	DueDate     string      `json:"duedate"`     // YYYY-MM-DD
 	Description interface{} `json:"description"` // Jira description can be string or object
 	Created     CustomTime  `json:"created"`
 	Updated     CustomTime  `json:"updated"`
@@ -48,6 +55,8 @@ type Comments struct {
 }
 
 type Comment struct {
+	ID      string     `json:"id"`
+	Self    string     `json:"self"`
 	Author  *User      `json:"author"`
 	Body    string     `json:"body"`
 	Created CustomTime `json:"created"`
@@ -55,30 +64,160 @@ type Comment struct {
 }
 
 type Status struct {
+	ID             string          `json:"id,omitempty"`
+	Name           string          `json:"name"`
+	StatusCategory *StatusCategory `json:"statusCategory,omitempty"`
+}
+
+type StatusCategory struct {
+	Key  string `json:"key"` // "new", "indeterminate" or "done"
 	Name string `json:"name"`
 }
 
+type Transition struct {
+	ID     string               `json:"id"`
+	Name   string               `json:"name"`
+	To     Status               `json:"to"`
+	Fields map[string]FieldMeta `json:"fields"`
+}
+
+// FieldMeta describes a field on a transition or create screen.
+type FieldMeta struct {
+	Required        bool           `json:"required"`
+	HasDefaultValue bool           `json:"hasDefaultValue"`
+	Name            string         `json:"name"`
+	Schema          FieldSchema    `json:"schema"`
+	AllowedValues   []AllowedValue `json:"allowedValues"`
+}
+
+type FieldSchema struct {
+	Type   string `json:"type"`
+	Items  string `json:"items,omitempty"`
+	System string `json:"system,omitempty"`
+	Custom string `json:"custom,omitempty"`
+}
+
+// AllowedValue is an option of a select-like field. Depending on the field
+// Jira fills in Name (resolutions, priorities) or Value (custom options).
+type AllowedValue struct {
+	ID    string `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// Label returns the text to show for the option.
+func (v AllowedValue) Label() string {
+	if v.Name != "" {
+		return v.Name
+	}
+	return v.Value
+}
+
 type IssueType struct {
 	Name string `json:"name"`
 }
 
 type User struct {
-	DisplayName string `json:"displayName"`
+	AccountID    string `json:"accountId,omitempty"` // Jira Cloud
+	Name         string `json:"name,omitempty"`      // username on Jira Server
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// Ref returns the reference Jira expects when a user is set as a field value.
+func (u User) Ref() map[string]string {
+	if u.AccountID != "" {
+		return map[string]string{"accountId": u.AccountID}
+	}
+	return map[string]string{"name": u.Name}
+}
+
+// Mention returns the wiki markup that mentions the user in a comment.
+func (u User) Mention() string {
+	if u.AccountID != "" {
+		return fmt.Sprintf("[~accountid:%s]", u.AccountID)
+	}
+	return fmt.Sprintf("[~%s]", u.Name)
 }
 
 type Priority struct {
 	Name string `json:"name"`
 }
 
+type Project struct {
+	ID   string `json:"id"`
+	Key  string `json:"key"`
+	Name string `json:"name"`
+}
+
+// CreateMetaIssueType is an issue type of a project together with the fields
+// of its create screen.
+type CreateMetaIssueType struct {
+	ID      string               `json:"id"`
+	Name    string               `json:"name"`
+	Subtask bool                 `json:"subtask"`
+	Fields  map[string]FieldMeta `json:"fields"`
+}
+
 type Board struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
 }
 
+// Filter is a saved Jira filter.
+type Filter struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	JQL  string `json:"jql"`
+}
+
+// BoardColumn is a column of a board with the statuses mapped to it.
+type BoardColumn struct {
+	Name     string `json:"name"`
+	Statuses []struct {
+		ID string `json:"id"`
+	} `json:"statuses"`
+}
+
+// JQLAutocompleteData lists the fields, functions and reserved words that can
+// be used in JQL.
+type JQLAutocompleteData struct {
+	VisibleFieldNames []struct {
+		Value       string   `json:"value"`
+		DisplayName string   `json:"displayName"`
+		Orderable   string   `json:"orderable"`
+		Operators   []string `json:"operators"`
+		Types       []string `json:"types"`
+	} `json:"visibleFieldNames"`
+	VisibleFunctionNames []struct {
+		Value string   `json:"value"`
+		Types []string `json:"types"`
+	} `json:"visibleFunctionNames"`
+	JQLReservedWords []string `json:"jqlReservedWords"`
+}
+
 type Sprint struct {
-	ID    int    `json:"id"`
-	Name  string `json:"name"`
-	State string `json:"state"`
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	State        string     `json:"state"` // "active", "future" or "closed"
+	Goal         string     `json:"goal"`
+	StartDate    CustomTime `json:"startDate"`
+	EndDate      CustomTime `json:"endDate"`
+	CompleteDate CustomTime `json:"completeDate"`
+}
+
+// ChangelogEntry is a set of changes made to an issue at once.
+type ChangelogEntry struct {
+	ID      string          `json:"id"`
+	Author  *User           `json:"author"`
+	Created CustomTime      `json:"created"`
+	Items   []ChangelogItem `json:"items"`
+}
+
+type ChangelogItem struct {
+	Field      string `json:"field"` // e.g. "status" or "assignee"
+	FromString string `json:"fromString"`
+	ToString   string `json:"toString"`
 }
 
 type CustomTime struct {
@@ -87,202 +226,355 @@ type CustomTime struct {
 
 const jiraTimeLayout = "2006-01-02T15:04:05.999-0700"
 
+// The platform API uses jiraTimeLayout, the agile API uses RFC 3339.
+var jiraTimeLayouts = []string{jiraTimeLayout, time.RFC3339Nano}
+
 func (ct *CustomTime) UnmarshalJSON(b []byte) (err error) {
 	s := string(b)
+	if s == "null" {
+		ct.Time = time.Time{}
+		return nil
+	}
 	s = s[1 : len(s)-1] // Remove quotes
-	ct.Time, err = time.Parse(jiraTimeLayout, s)
+	for _, layout := range jiraTimeLayouts {
+		if ct.Time, err = time.Parse(layout, s); err == nil {
+			return nil
+		}
+	}
 	return
 }
 
-// --- Helper Functions for Jira API ---
+// MarshalJSON writes the time in the layout Jira uses, so that issues
+// stored on disk read back the same.
+func (ct CustomTime) MarshalJSON() ([]byte, error) {
+	if ct.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + ct.Format(jiraTimeLayout) + `"`), nil
+}
 
-// FetchJiraStatuses fetches all available statuses from Jira
-func FetchJiraStatuses(apiToken string) ([]Status, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
-	url := fmt.Sprintf("%s/rest/api/2/status", jiraBaseURL)
+// --- Jira API Endpoints ---
 
-	req, err := http.NewRequest("GET", url, nil)
+// FetchJiraStatuses fetches all available statuses from Jira
+func (c *JiraClient) FetchJiraStatuses(ctx context.Context) ([]Status, error) {
+	statuses, err := getJSON[[]Status](ctx, c, "/rest/api/2/status", nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, fmt.Errorf("error fetching statuses: %w", err)
 	}
-	req.SetBasicAuth("huannguyenh@vus-etsc.edu.vn", apiToken)
+	return statuses, nil
+}
 
-	resp, err := client.Do(req)
+// FetchJiraUsers fetches all active users from Jira
+func (c *JiraClient) FetchJiraUsers(ctx context.Context) ([]User, error) {
+	// Jira Cloud API for user search requires a query parameter, e.g., 'query=.' for all users
+	users, err := fetchListPages[User](ctx, c, "/rest/api/2/user/search", url.Values{"query": {"."}})
 	if err != nil {
-		return nil, fmt.Errorf("error making request to Jira: %w", err)
+		return nil, fmt.Errorf("error fetching users: %w", err)
 	}
-	defer resp.Body.Close()
+	return users, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Jira API returned non-OK status: %s Response: %s", resp.Status, string(bodyBytes))
+// FetchJiraBoards fetches all boards from Jira
+func (c *JiraClient) FetchJiraBoards(ctx context.Context) ([]Board, error) {
+	boards, err := fetchAgileValues[Board](ctx, c, "/rest/agile/1.0/board", nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching boards: %w", err)
 	}
+	return boards, nil
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+// FetchJiraSprints fetches all sprints for a given board from Jira
+func (c *JiraClient) FetchJiraSprints(ctx context.Context, boardID int) ([]Sprint, error) {
+	sprints, err := fetchAgileValues[Sprint](ctx, c, fmt.Sprintf("/rest/agile/1.0/board/%d/sprint", boardID), nil, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, fmt.Errorf("error fetching sprints: %w", err)
 	}
+	return sprints, nil
+}
 
-	var statuses []Status
-	if err := json.Unmarshal(bodyBytes, &statuses); err != nil {
-		return nil, fmt.Errorf("error unmarshalling statuses: %w", err)
+// FetchBoardColumns fetches the column configuration of a board.
+func (c *JiraClient) FetchBoardColumns(ctx context.Context, boardID int) ([]BoardColumn, error) {
+	config, err := getJSON[struct {
+		ColumnConfig struct {
+			Columns []BoardColumn `json:"columns"`
+		} `json:"columnConfig"`
+	}](ctx, c, fmt.Sprintf("/rest/agile/1.0/board/%d/configuration", boardID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching board configuration: %w", err)
 	}
+	return config.ColumnConfig.Columns, nil
+}
 
-	return statuses, nil
+// FetchSprintIssues returns the issues of a sprint, with the same paging and
+// onPage behaviour as FetchJiraIssues.
+func (c *JiraClient) FetchSprintIssues(ctx context.Context, sprintID int, onPage func([]Issue)) ([]Issue, error) {
+	params := url.Values{}
+	params.Add("fields", issueFields)
+	issues, err := c.searchIssuesByOffset(ctx, fmt.Sprintf("/rest/agile/1.0/sprint/%d/issue", sprintID), params, c.MaxResults, onPage)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching sprint issues: %w", err)
+	}
+	return issues, nil
 }
 
-// FetchJiraUsers fetches all active users from Jira
-func FetchJiraUsers(apiToken string) ([]User, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
-	// Jira Cloud API for user search requires a query parameter, e.g., 'query=.' for all users
-	url := fmt.Sprintf("%s/rest/api/2/user/search?query=.", jiraBaseURL)
+// FetchJiraIssues runs a JQL search and returns all matching issues, up to the
+// client's MaxResults. If onPage is not nil it is called with every page as
+// soon as it has been received.
+func (c *JiraClient) FetchJiraIssues(ctx context.Context, jql string, onPage func([]Issue)) ([]Issue, error) {
+	params := url.Values{}
+	params.Add("jql", jql)
+	// Requesting specific fields
+	params.Add("fields", issueFields)
+	params.Add("expand", "renderedFields")
 
-	req, err := http.NewRequest("GET", url, nil)
+	issues, err := c.searchJQL(ctx, params, c.MaxResults, onPage)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, fmt.Errorf("error fetching issues: %w", err)
 	}
-	req.SetBasicAuth("huannguyenh@vus-etsc.edu.vn", apiToken)
+	return issues, nil
+}
 
-	resp, err := client.Do(req)
+// FetchIssueKeys returns the keys of all the issues matching jql, in the
+// query's order, without fetching their fields. It is not capped by the
+// client's MaxResults, so a query matching a large part of the instance
+// takes one request per page of keys.
+func (c *JiraClient) FetchIssueKeys(ctx context.Context, jql string) ([]string, error) {
+	params := url.Values{}
+	params.Add("jql", jql)
+	params.Add("fields", "key")
+
+	issues, err := c.searchJQL(ctx, params, 0, nil)
 	if err != nil {
-		return nil, fmt.Errorf("error making request to Jira: %w", err)
+		return nil, fmt.Errorf("error fetching issue keys: %w", err)
+	}
+	keys := make([]string, len(issues))
+	for i, issue := range issues {
+		keys[i] = issue.Key
 	}
-	defer resp.Body.Close()
+	return keys, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Jira API returned non-OK status: %s Response: %s", resp.Status, string(bodyBytes))
+// searchJQL pages through a JQL search with the configured search API, up to
+// limit issues, or all of them when limit is 0.
+func (c *JiraClient) searchJQL(ctx context.Context, params url.Values, limit int, onPage func([]Issue)) ([]Issue, error) {
+	if c.SearchAPI == searchAPIToken {
+		return c.searchIssuesByToken(ctx, jiraTokenAPIPath, params, limit, onPage)
 	}
+	return c.searchIssuesByOffset(ctx, jiraAPIPath, params, limit, onPage)
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+// FetchTransitions fetches the transitions currently available for an issue,
+// including the fields of their transition screens.
+func (c *JiraClient) FetchTransitions(ctx context.Context, issueKey string) ([]Transition, error) {
+	resp, err := getJSON[struct {
+		Transitions []Transition `json:"transitions"`
+	}](ctx, c, issuePath(issueKey, "transitions"), url.Values{"expand": {"transitions.fields"}})
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, fmt.Errorf("error fetching transitions: %w", err)
 	}
+	return resp.Transitions, nil
+}
 
-	var users []User
-	if err := json.Unmarshal(bodyBytes, &users); err != nil {
-		return nil, fmt.Errorf("error unmarshalling users: %w", err)
+// TransitionIssue moves an issue through a transition. fields holds values
+// for the transition screen and comment, when not empty, is added as a comment.
+func (c *JiraClient) TransitionIssue(ctx context.Context, issueKey, transitionID string, fields map[string]interface{}, comment string) error {
+	payload := map[string]interface{}{
+		"transition": map[string]string{"id": transitionID},
 	}
-
-	return users, nil
+	if len(fields) > 0 {
+		payload["fields"] = fields
+	}
+	if comment != "" {
+		payload["update"] = map[string]interface{}{
+			"comment": []interface{}{
+				map[string]interface{}{"add": map[string]string{"body": comment}},
+			},
+		}
+	}
+	if err := c.Post(ctx, issuePath(issueKey, "transitions"), payload, nil); err != nil {
+		return fmt.Errorf("error transitioning %s: %w", issueKey, err)
+	}
+	return nil
 }
 
-// FetchJiraBoards fetches all boards from Jira
-func FetchJiraBoards(apiToken string) ([]Board, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
-	url := fmt.Sprintf("%s/rest/agile/1.0/board", jiraBaseURL)
-
-	req, err := http.NewRequest("GET", url, nil)
+// AddComment adds a comment to an issue and returns it as created by Jira.
+func (c *JiraClient) AddComment(ctx context.Context, issueKey, body string) (Comment, error) {
+	comment, err := postJSON[Comment](ctx, c, issuePath(issueKey, "comment"), map[string]string{"body": body})
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return Comment{}, fmt.Errorf("error adding comment to %s: %w", issueKey, err)
 	}
-	req.SetBasicAuth("huannguyenh@vus-etsc.edu.vn", apiToken)
+	return comment, nil
+}
 
-	resp, err := client.Do(req)
+// UpdateComment replaces the body of one of an issue's comments.
+func (c *JiraClient) UpdateComment(ctx context.Context, issueKey, commentID, body string) (Comment, error) {
+	comment, err := putJSON[Comment](ctx, c, issuePath(issueKey, "comment", commentID), map[string]string{"body": body})
 	if err != nil {
-		return nil, fmt.Errorf("error making request to Jira: %w", err)
+		return Comment{}, fmt.Errorf("error updating comment on %s: %w", issueKey, err)
 	}
-	defer resp.Body.Close()
+	return comment, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Jira API returned non-OK status: %s Response: %s", resp.Status, string(bodyBytes))
+// DeleteComment deletes one of an issue's comments. A comment that is gone
+// when a failed attempt is retried counts as deleted.
+func (c *JiraClient) DeleteComment(ctx context.Context, issueKey, commentID string) error {
+	if err := c.Delete(ctx, issuePath(issueKey, "comment", commentID)); err != nil {
+		return fmt.Errorf("error deleting comment on %s: %w", issueKey, err)
 	}
+	return nil
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+// FetchMyself fetches the user the client is authenticated as.
+func (c *JiraClient) FetchMyself(ctx context.Context) (User, error) {
+	me, err := getJSON[User](ctx, c, "/rest/api/2/myself", nil)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return User{}, fmt.Errorf("error fetching current user: %w", err)
 	}
+	return me, nil
+}
 
-	var boardResponse struct {
-		Values []Board `json:"values"`
-	}
-	if err := json.Unmarshal(bodyBytes, &boardResponse); err != nil {
-		return nil, fmt.Errorf("error unmarshalling boards: %w", err)
+// FetchIssue fetches a single issue with the fields shown in the list.
+func (c *JiraClient) FetchIssue(ctx context.Context, issueKey string) (Issue, error) {
+	issue, err := getJSON[Issue](ctx, c, issuePath(issueKey), url.Values{"fields": {issueFields}})
+	if err != nil {
+		return Issue{}, fmt.Errorf("error fetching %s: %w", issueKey, err)
 	}
-
-	return boardResponse.Values, nil
+	return issue, nil
 }
 
-// FetchJiraSprints fetches all sprints for a given board from Jira
-func FetchJiraSprints(apiToken string, boardID int) ([]Sprint, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
-	url := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint", jiraBaseURL, boardID)
-
-	req, err := http.NewRequest("GET", url, nil)
+// FetchProjects fetches the projects visible to the current user.
+func (c *JiraClient) FetchProjects(ctx context.Context) ([]Project, error) {
+	projects, err := getJSON[[]Project](ctx, c, "/rest/api/2/project", nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, fmt.Errorf("error fetching projects: %w", err)
 	}
-	req.SetBasicAuth("huannguyenh@vus-etsc.edu.vn", apiToken)
+	return projects, nil
+}
 
-	resp, err := client.Do(req)
+// FetchCreateMeta fetches the issue types of a project with the fields of
+// their create screens.
+func (c *JiraClient) FetchCreateMeta(ctx context.Context, projectKey string) ([]CreateMetaIssueType, error) {
+	resp, err := getJSON[struct {
+		Projects []struct {
+			IssueTypes []CreateMetaIssueType `json:"issuetypes"`
+		} `json:"projects"`
+	}](ctx, c, "/rest/api/2/issue/createmeta", url.Values{
+		"projectKeys": {projectKey},
+		"expand":      {"projects.issuetypes.fields"},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error making request to Jira: %w", err)
+		return nil, fmt.Errorf("error fetching create metadata: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Jira API returned non-OK status: %s Response: %s", resp.Status, string(bodyBytes))
+	if len(resp.Projects) == 0 {
+		return nil, fmt.Errorf("you cannot create issues in %s", projectKey)
 	}
+	return resp.Projects[0].IssueTypes, nil
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+// CreateIssue creates an issue from the given field values and returns its key.
+func (c *JiraClient) CreateIssue(ctx context.Context, fields map[string]interface{}) (string, error) {
+	created, err := postJSON[struct {
+		ID  string `json:"id"`
+		Key string `json:"key"`
+	}](ctx, c, "/rest/api/2/issue", map[string]interface{}{"fields": fields})
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return "", fmt.Errorf("error creating issue: %w", err)
 	}
+	return created.Key, nil
+}
 
-	var sprintResponse struct {
-		Values []Sprint `json:"values"`
+// AssignIssue assigns an issue to user, or unassigns it when user is nil.
+func (c *JiraClient) AssignIssue(ctx context.Context, issueKey string, user *User) error {
+	var body interface{}
+	switch {
+	case user != nil:
+		body = user.Ref()
+	case c.isCloud():
+		body = map[string]interface{}{"accountId": nil}
+	default:
+		body = map[string]interface{}{"name": nil}
 	}
-	if err := json.Unmarshal(bodyBytes, &sprintResponse); err != nil {
-		return nil, fmt.Errorf("error unmarshalling sprints: %w", err)
+	if err := c.Put(ctx, issuePath(issueKey, "assignee"), body, nil); err != nil {
+		return fmt.Errorf("error assigning %s: %w", issueKey, err)
 	}
-
-	return sprintResponse.Values, nil
+	return nil
 }
 
-func FetchJiraIssues(email, apiToken string, jql string) ([]Issue, error) {
-	client := &http.Client{Timeout: 20 * time.Second}
-	jiraURL, err := url.Parse(fmt.Sprintf("%s%s", jiraBaseURL, jiraAPIPath))
+// FetchAssignableUsers searches the users that can be assigned issues in a
+// project, or a specific issue when issueKey is set.
+func (c *JiraClient) FetchAssignableUsers(ctx context.Context, projectKey, issueKey, query string) ([]User, error) {
+	params := url.Values{}
+	if issueKey != "" {
+		params.Set("issueKey", issueKey)
+	} else {
+		params.Set("project", projectKey)
+	}
+	if query != "" {
+		params.Set("query", query)
+	}
+	users, err := fetchListPages[User](ctx, c, "/rest/api/2/user/assignable/search", params)
 	if err != nil {
-		return nil, fmt.Errorf("error parsing Jira URL: %w", err)
+		return nil, fmt.Errorf("error fetching assignable users: %w", err)
 	}
+	return users, nil
+}
 
-	params := url.Values{}
-	params.Add("jql", jql)
-	params.Add("maxResults", "100")
-	// Requesting specific fields
-	params.Add("fields", "summary,status,issuetype,assignee,reporter,priority,description,created,updated,comment")
-	params.Add("expand", "renderedFields")
-	jiraURL.RawQuery = params.Encode()
-
-	req, err := http.NewRequest("GET", jiraURL.String(), nil)
+// FetchJQLAutocompleteData fetches the field and function names known to JQL.
+func (c *JiraClient) FetchJQLAutocompleteData(ctx context.Context) (JQLAutocompleteData, error) {
+	data, err := getJSON[JQLAutocompleteData](ctx, c, "/rest/api/2/jql/autocompletedata", nil)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return JQLAutocompleteData{}, fmt.Errorf("error fetching JQL autocomplete data: %w", err)
 	}
+	return data, nil
+}
 
-	req.SetBasicAuth(email, apiToken)
-
-	resp, err := client.Do(req)
+// FetchJQLSuggestions fetches values of fieldName starting with prefix.
+func (c *JiraClient) FetchJQLSuggestions(ctx context.Context, fieldName, prefix string) ([]string, error) {
+	resp, err := getJSON[struct {
+		Results []struct {
+			Value string `json:"value"`
+		} `json:"results"`
+	}](ctx, c, "/rest/api/2/jql/autocompletedata/suggestions", url.Values{
+		"fieldName":  {fieldName},
+		"fieldValue": {prefix},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("error making request to Jira: %w", err)
+		return nil, fmt.Errorf("error fetching JQL suggestions: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Jira API returned non-OK status: %s Response: %s", resp.Status, string(bodyBytes))
+	values := make([]string, len(resp.Results))
+	for i, r := range resp.Results {
+		values[i] = r.Value
 	}
+	return values, nil
+}
 
-	bodyBytes, err := io.ReadAll(resp.Body)
+// FetchFavouriteFilters fetches the filters the user marked as favourite.
+func (c *JiraClient) FetchFavouriteFilters(ctx context.Context) ([]Filter, error) {
+	filters, err := getJSON[[]Filter](ctx, c, "/rest/api/2/filter/favourite", nil)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response body: %w", err)
+		return nil, fmt.Errorf("error fetching favourite filters: %w", err)
 	}
+	return filters, nil
+}
 
-	var jiraResponse JiraSearchResponse
-	if err := json.Unmarshal(bodyBytes, &jiraResponse); err != nil {
-		return nil, fmt.Errorf("error unmarshalling JSON: %w", err)
+// FetchChangelog fetches the history of changes to an issue, oldest first.
+func (c *JiraClient) FetchChangelog(ctx context.Context, issueKey string) ([]ChangelogEntry, error) {
+	resp, err := getJSON[struct {
+		Changelog struct {
+			Histories []ChangelogEntry `json:"histories"`
+		} `json:"changelog"`
+	}](ctx, c, issuePath(issueKey), url.Values{"fields": {"updated"}, "expand": {"changelog"}})
+	if err != nil {
+		return nil, fmt.Errorf("error fetching changelog of %s: %w", issueKey, err)
 	}
+	return resp.Changelog.Histories, nil
+}
 
-	return jiraResponse.Issues, nil
+// issuePath builds /rest/api/2/issue/{key}[/sub/...].
+func issuePath(issueKey string, sub ...string) string {
+	path := "/rest/api/2/issue/" + url.PathEscape(issueKey)
+	for _, s := range sub {
+		path += "/" + url.PathEscape(s)
+	}
+	return path
 }
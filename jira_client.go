@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const defaultUserAgent = "jira-cli"
+
+// JiraClient talks to a single Jira site. It owns the credentials and the
+// HTTP transport so that endpoint methods only describe what to request.
+type JiraClient struct {
+	BaseURL    string
+	Email      string
+	APIToken   string
+	AuthType   string
+	UserAgent  string
+	HTTPClient *http.Client
+
+	MaxResults int    // cap for issue searches, 0 for no cap
+	SearchAPI  string // searchAPIOffset or searchAPIToken
+	MaxRetries int    // retries for rate limited or failed requests
+}
+
+// NewJiraClient creates a client for the site described by cfg.
+func NewJiraClient(cfg Config) *JiraClient {
+	return &JiraClient{
+		BaseURL:    cfg.BaseURL,
+		Email:      cfg.Email,
+		APIToken:   cfg.APIToken,
+		AuthType:   cfg.AuthType,
+		UserAgent:  defaultUserAgent,
+		HTTPClient: &http.Client{Timeout: 20 * time.Second},
+		MaxResults: cfg.MaxResults,
+		SearchAPI:  cfg.SearchAPI,
+		MaxRetries: defaultMaxRetries,
+	}
+}
+
+// BrowseURL returns the web URL of an issue.
+func (c *JiraClient) BrowseURL(issueKey string) string {
+	return fmt.Sprintf("%s/browse/%s", c.BaseURL, issueKey)
+}
+
+// isCloud reports whether the client talks to Jira Cloud rather than Jira
+// Server / Data Center, whose APIs differ in a few places.
+func (c *JiraClient) isCloud() bool {
+	return isCloudURL(c.BaseURL)
+}
+
+// authorize adds the configured credentials to a request.
+func (c *JiraClient) authorize(req *http.Request) {
+	if c.AuthType == authTypeBearer {
+		req.Header.Set("Authorization", "Bearer "+c.APIToken)
+		return
+	}
+	req.SetBasicAuth(c.Email, c.APIToken)
+}
+
+// Get requests path with the given query and decodes the JSON response into out.
+func (c *JiraClient) Get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// Post sends body as JSON to path and decodes the JSON response into out.
+func (c *JiraClient) Post(ctx context.Context, path string, body, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, nil, body, out)
+}
+
+// Put sends body as JSON to path and decodes the JSON response into out.
+func (c *JiraClient) Put(ctx context.Context, path string, body, out interface{}) error {
+	return c.do(ctx, http.MethodPut, path, nil, body, out)
+}
+
+// Delete deletes the resource at path.
+func (c *JiraClient) Delete(ctx context.Context, path string) error {
+	return c.do(ctx, http.MethodDelete, path, nil, nil, nil)
+}
+
+// do performs a request against the Jira REST API. body, when not nil, is
+// encoded as JSON; out, when not nil, receives the decoded response.
+// Failed requests are retried with exponential backoff when it is safe to do
+// so, see shouldRetry and notConnected. Cancelling ctx aborts the request and
+// any pending retry.
+func (c *JiraClient) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	reqURL, err := url.Parse(c.BaseURL + path)
+	if err != nil {
+		return fmt.Errorf("error parsing Jira URL: %w", err)
+	}
+	if len(query) > 0 {
+		reqURL.RawQuery = query.Encode()
+	}
+
+	var payload []byte
+	if body != nil {
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error encoding request body: %w", err)
+		}
+	}
+
+	maybeApplied := false // an earlier attempt may have been carried out
+	for attempt := 0; ; attempt++ {
+		resp, respBody, err := c.send(ctx, method, reqURL.String(), payload)
+		if err != nil {
+			if ctx.Err() == nil && attempt < c.MaxRetries && notConnected(err) {
+				if err := sleepContext(ctx, backoff(attempt)); err != nil {
+					return err
+				}
+				continue
+			}
+			return fmt.Errorf("error making request to Jira: %w", err)
+		}
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			// The resource is gone: deleted by the attempt whose response
+			// was an error.
+			if method == http.MethodDelete && resp.StatusCode == http.StatusNotFound && maybeApplied {
+				return nil
+			}
+			if attempt < c.MaxRetries && shouldRetry(method, resp.StatusCode) {
+				maybeApplied = maybeApplied || resp.StatusCode != http.StatusTooManyRequests
+				wait, ok := retryAfter(resp)
+				if !ok {
+					wait = backoff(attempt)
+				}
+				if wait > retryMaxDelay {
+					// Retrying any sooner than Jira asked would only be
+					// rejected again: give up and let the error say when.
+					return newJiraError(resp, respBody)
+				}
+				if err := sleepContext(ctx, wait); err != nil {
+					return err
+				}
+				continue
+			}
+			return newJiraError(resp, respBody)
+		}
+
+		if out == nil || len(respBody) == 0 {
+			return nil
+		}
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("error unmarshalling JSON: %w", err)
+		}
+		return nil
+	}
+}
+
+// send performs a single HTTP round trip and returns the response together
+// with its fully read body.
+func (c *JiraClient) send(ctx context.Context, method, reqURL string, payload []byte) (*http.Response, []byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating request: %w", err)
+	}
+	c.authorize(req)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response body: %w", err)
+	}
+	return resp, respBody, nil
+}
+
+// --- Retries ---
+
+const (
+	defaultMaxRetries = 3
+	retryBaseDelay    = 500 * time.Millisecond
+	retryMaxDelay     = 30 * time.Second // longer waits are not waited for
+)
+
+// isIdempotent reports whether a request can be repeated without side effects
+// if we don't know whether the first attempt reached Jira.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// notConnected reports whether a request failed because no connection to
+// Jira could be made, so that it never reached Jira and can be retried
+// whatever its method. Timeouts are not retried, as every attempt could take
+// the whole client timeout and an unreachable network is better reported
+// straight away.
+func notConnected(err error) bool {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return false
+	}
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	return errors.As(err, &dnsErr) || errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// shouldRetry reports whether a response with the given status is worth
+// retrying. A 429 means Jira rejected the request before processing it, so
+// even non-idempotent requests can be retried; server errors only for
+// idempotent ones.
+func shouldRetry(method string, statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return isIdempotent(method)
+	}
+	return false
+}
+
+// retryAfter parses the Retry-After header, which Jira sends either as a
+// number of seconds or as an HTTP date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// backoff returns an exponentially growing delay with full jitter.
+func backoff(attempt int) time.Duration {
+	delay := min(retryBaseDelay<<min(attempt, 10), retryMaxDelay)
+	return time.Duration(rand.Int64N(int64(delay))) + time.Millisecond
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// getJSON is a typed wrapper around JiraClient.Get.
+func getJSON[T any](ctx context.Context, c *JiraClient, path string, query url.Values) (T, error) {
+	var out T
+	err := c.Get(ctx, path, query, &out)
+	return out, err
+}
+
+// postJSON is a typed wrapper around JiraClient.Post.
+func postJSON[T any](ctx context.Context, c *JiraClient, path string, body interface{}) (T, error) {
+	var out T
+	err := c.Post(ctx, path, body, &out)
+	return out, err
+}
+
+// putJSON is a typed wrapper around JiraClient.Put.
+func putJSON[T any](ctx context.Context, c *JiraClient, path string, body interface{}) (T, error) {
+	var out T
+	err := c.Put(ctx, path, body, &out)
+	return out, err
+}
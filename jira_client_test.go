@@ -0,0 +1,570 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testReply is what the test server answers to a request.
+type testReply struct {
+	status int // 200 when zero
+	header map[string]string
+	body   interface{} // written as is if a string, else encoded as JSON
+}
+
+// newTestClient returns a client talking to a test server that answers with
+// reply, which is given the number of requests made before r, and a counter
+// of the requests made.
+func newTestClient(t *testing.T, reply func(n int, r *http.Request) testReply) (*JiraClient, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out := reply(int(requests.Add(1))-1, r)
+		for name, value := range out.header {
+			w.Header().Set(name, value)
+		}
+		body, ok := out.body.(string)
+		if !ok && out.body != nil {
+			data, err := json.Marshal(out.body)
+			if err != nil {
+				t.Errorf("encoding response: %v", err)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			body = string(data)
+		}
+		if out.status != 0 {
+			w.WriteHeader(out.status)
+		}
+		io.WriteString(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return &JiraClient{
+		BaseURL:    server.URL,
+		Email:      "me@example.com",
+		APIToken:   "secret",
+		UserAgent:  defaultUserAgent,
+		HTTPClient: server.Client(),
+		MaxRetries: defaultMaxRetries,
+	}, &requests
+}
+
+// testIssues returns n issues numbered from first, as Jira sends them.
+func testIssues(first, n int) []map[string]string {
+	issues := make([]map[string]string, n)
+	for i := range issues {
+		issues[i] = map[string]string{"key": fmt.Sprintf("TEST-%d", first+i)}
+	}
+	return issues
+}
+
+// queryInt returns an integer parameter of the query of r.
+func queryInt(r *http.Request, name string) int {
+	n, _ := strconv.Atoi(r.URL.Query().Get(name))
+	return n
+}
+
+func TestClientRequest(t *testing.T) {
+	for _, authType := range []string{authTypeBasic, authTypeBearer} {
+		t.Run(authType, func(t *testing.T) {
+			client, _ := newTestClient(t, func(n int, r *http.Request) testReply {
+				user, password, basic := r.BasicAuth()
+				switch {
+				case authType == authTypeBearer && r.Header.Get("Authorization") != "Bearer secret":
+					t.Errorf("Authorization = %q, want the bearer token", r.Header.Get("Authorization"))
+				case authType == authTypeBasic && (!basic || user != "me@example.com" || password != "secret"):
+					t.Errorf("basic auth = %q, %q, %v", user, password, basic)
+				}
+				if got := r.Header.Get("User-Agent"); got != defaultUserAgent {
+					t.Errorf("User-Agent = %q, want %q", got, defaultUserAgent)
+				}
+				if r.Method != http.MethodPost || r.URL.Path != "/rest/api/2/issue/TEST-1/comment" {
+					t.Errorf("request = %s %s", r.Method, r.URL.Path)
+				}
+				if got := r.Header.Get("Content-Type"); got != "application/json" {
+					t.Errorf("Content-Type = %q", got)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"body":"hello"}` {
+					t.Errorf("body = %s", body)
+				}
+				return testReply{body: map[string]string{"id": "10", "body": "hello"}}
+			})
+			client.AuthType = authType
+
+			comment, err := postJSON[map[string]string](context.Background(), client, "/rest/api/2/issue/TEST-1/comment", map[string]string{"body": "hello"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if comment["id"] != "10" || comment["body"] != "hello" {
+				t.Errorf("comment = %v", comment)
+			}
+		})
+	}
+}
+
+func TestAppendCapped(t *testing.T) {
+	tests := []struct {
+		limit, items, page  int
+		wantItems, wantKept int
+		wantCapped          bool
+	}{
+		{limit: 0, items: 0, page: 100, wantItems: 100, wantKept: 100},
+		{limit: 0, items: 500, page: 0, wantItems: 500},
+		{limit: 150, items: 100, page: 100, wantItems: 150, wantKept: 50, wantCapped: true},
+		{limit: 150, items: 0, page: 100, wantItems: 100, wantKept: 100},
+		{limit: 100, items: 0, page: 100, wantItems: 100, wantKept: 100, wantCapped: true},
+		{limit: 100, items: 100, page: 0, wantItems: 100, wantCapped: true},
+		{limit: 10, items: 0, page: 0, wantItems: 0},
+	}
+	for _, tt := range tests {
+		items, kept, capped := appendCapped(tt.limit, make([]int, tt.items), make([]int, tt.page))
+		if len(items) != tt.wantItems || len(kept) != tt.wantKept || capped != tt.wantCapped {
+			t.Errorf("appendCapped(%d, %d items, %d) = %d, %d, %v, want %d, %d, %v", tt.limit, tt.items, tt.page,
+				len(items), len(kept), capped, tt.wantItems, tt.wantKept, tt.wantCapped)
+		}
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	tests := []struct {
+		limit, fetched, want int
+	}{
+		{0, 0, defaultPageSize},
+		{0, 1000, defaultPageSize},
+		{250, 200, 50},
+		{250, 100, defaultPageSize},
+		{30, 0, 30},
+	}
+	for _, tt := range tests {
+		if got := pageSize(tt.limit, tt.fetched); got != tt.want {
+			t.Errorf("pageSize(%d, %d) = %d, want %d", tt.limit, tt.fetched, got, tt.want)
+		}
+	}
+}
+
+func TestSearchIssuesByOffset(t *testing.T) {
+	tests := []struct {
+		name       string
+		total      int // issues matching the query
+		served     int // issues actually served, fewer when the total is wrong
+		maxResults int
+		want       int
+		requests   int
+	}{
+		{name: "one page", total: 30, served: 30, want: 30, requests: 1},
+		{name: "several pages", total: 250, served: 250, want: 250, requests: 3},
+		{name: "exact pages", total: 200, served: 200, want: 200, requests: 2},
+		{name: "empty", want: 0, requests: 1},
+		{name: "capped", total: 250, served: 250, maxResults: 150, want: 150, requests: 2},
+		{name: "total too high", total: 500, served: 120, want: 120, requests: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var served int
+			client, requests := newTestClient(t, func(n int, r *http.Request) testReply {
+				if got := r.URL.Query().Get("jql"); got != "project = TEST" {
+					t.Errorf("jql = %q", got)
+				}
+				startAt, size := queryInt(r, "startAt"), queryInt(r, "maxResults")
+				if startAt != served {
+					t.Errorf("request %d: startAt = %d, want %d", n, startAt, served)
+				}
+				count := max(min(size, tt.served-startAt), 0)
+				served += count
+				return testReply{body: map[string]interface{}{"startAt": startAt, "maxResults": size, "total": tt.total, "issues": testIssues(startAt, count)}}
+			})
+			client.SearchAPI = searchAPIOffset
+			client.MaxResults = tt.maxResults
+
+			var streamed int
+			issues, err := client.FetchJiraIssues(context.Background(), "project = TEST", func(page []Issue) {
+				streamed += len(page)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != tt.want || streamed != tt.want || int(requests.Load()) != tt.requests {
+				t.Errorf("got %d issues, %d streamed in %d requests, want %d in %d", len(issues), streamed, requests.Load(), tt.want, tt.requests)
+			}
+			for i, issue := range issues {
+				if want := fmt.Sprintf("TEST-%d", i); issue.Key != want {
+					t.Fatalf("issue %d = %s, want %s", i, issue.Key, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchIssuesByToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		pages      int
+		isLast     bool // whether the last page says so, or only omits the token
+		maxResults int
+		want       int
+		requests   int
+	}{
+		{name: "isLast", pages: 3, isLast: true, want: 300, requests: 3},
+		{name: "no token", pages: 2, want: 200, requests: 2},
+		{name: "capped", pages: 3, isLast: true, maxResults: 120, want: 120, requests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newTestClient(t, func(n int, r *http.Request) testReply {
+				if r.URL.Path != jiraTokenAPIPath {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				want := ""
+				if n > 0 {
+					want = fmt.Sprintf("token-%d", n)
+				}
+				if got := r.URL.Query().Get("nextPageToken"); got != want {
+					t.Errorf("request %d: nextPageToken = %q, want %q", n, got, want)
+				}
+				resp := map[string]interface{}{"issues": testIssues(n*defaultPageSize, queryInt(r, "maxResults"))}
+				if n+1 < tt.pages {
+					resp["nextPageToken"] = fmt.Sprintf("token-%d", n+1)
+				} else {
+					resp["isLast"] = tt.isLast
+				}
+				return testReply{body: resp}
+			})
+			client.SearchAPI = searchAPIToken
+			client.MaxResults = tt.maxResults
+
+			issues, err := client.FetchJiraIssues(context.Background(), "project = TEST", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(issues) != tt.want || int(requests.Load()) != tt.requests {
+				t.Errorf("got %d issues in %d requests, want %d in %d", len(issues), requests.Load(), tt.want, tt.requests)
+			}
+		})
+	}
+}
+
+func TestFetchIssueKeys(t *testing.T) {
+	for _, api := range []string{searchAPIOffset, searchAPIToken} {
+		t.Run(api, func(t *testing.T) {
+			client, requests := newTestClient(t, func(n int, r *http.Request) testReply {
+				if got := r.URL.Query().Get("fields"); got != "key" {
+					t.Errorf("fields = %q, want key", got)
+				}
+				startAt, size := n*defaultPageSize, queryInt(r, "maxResults")
+				count := max(min(size, 250-startAt), 0)
+				resp := map[string]interface{}{"startAt": startAt, "total": 250, "issues": testIssues(startAt, count)}
+				if startAt+count < 250 {
+					resp["nextPageToken"] = fmt.Sprintf("token-%d", n+1)
+				} else {
+					resp["isLast"] = true
+				}
+				return testReply{body: resp}
+			})
+			client.SearchAPI = api
+			// The keys tell which cached issues left the query, they must
+			// all be fetched whatever the cap.
+			client.MaxResults = 50
+
+			keys, err := client.FetchIssueKeys(context.Background(), "project = TEST")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(keys) != 250 || requests.Load() != 3 {
+				t.Errorf("got %d keys in %d requests, want 250 in 3", len(keys), requests.Load())
+			}
+		})
+	}
+}
+
+func TestFetchAgileValues(t *testing.T) {
+	client, requests := newTestClient(t, func(n int, r *http.Request) testReply {
+		startAt, size := queryInt(r, "startAt"), queryInt(r, "maxResults")
+		if size != defaultPageSize {
+			t.Errorf("maxResults = %d, want %d", size, defaultPageSize)
+		}
+		var sprints []Sprint
+		for i := startAt; i < min(startAt+size, 230); i++ {
+			sprints = append(sprints, Sprint{ID: i})
+		}
+		return testReply{body: agilePage[Sprint]{StartAt: startAt, MaxResults: size, IsLast: startAt+size >= 230, Values: sprints}}
+	})
+	// The cap only applies to issues: the active sprints come last.
+	client.MaxResults = 50
+
+	sprints, err := client.FetchJiraSprints(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sprints) != 230 || requests.Load() != 3 {
+		t.Errorf("got %d sprints in %d requests, want 230 in 3", len(sprints), requests.Load())
+	}
+}
+
+func TestFetchListPages(t *testing.T) {
+	// Pages of 100 users with some hidden, as Jira Cloud does, then nothing.
+	shown := []int{60, 100, 30, 0}
+	client, requests := newTestClient(t, func(n int, r *http.Request) testReply {
+		if startAt := queryInt(r, "startAt"); startAt != n*defaultPageSize {
+			t.Errorf("request %d: startAt = %d, want %d", n, startAt, n*defaultPageSize)
+		}
+		users := []User{}
+		for i := 0; i < shown[min(n, len(shown)-1)]; i++ {
+			users = append(users, User{AccountID: fmt.Sprintf("%d-%d", n, i)})
+		}
+		return testReply{body: users}
+	})
+	client.MaxResults = 50
+
+	users, err := fetchListPages[User](context.Background(), client, "/rest/api/2/user/search", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 190 || requests.Load() != 4 {
+		t.Errorf("got %d users in %d requests, want 190 in 4", len(users), requests.Load())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "0", want: 0, wantOK: true},
+		{value: "5", want: 5 * time.Second, wantOK: true},
+		{value: "3600", want: time.Hour, wantOK: true},
+		{value: "-1", wantOK: false},
+		{value: "soon", wantOK: false},
+		{value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), want: 0, wantOK: true},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{Header: http.Header{}}
+		if tt.value != "" {
+			resp.Header.Set("Retry-After", tt.value)
+		}
+		got, ok := retryAfter(resp)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("retryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	for _, in := range []time.Duration{10 * time.Second, time.Hour} {
+		resp := &http.Response{Header: http.Header{"Retry-After": {time.Now().Add(in).UTC().Format(http.TimeFormat)}}}
+		if got, ok := retryAfter(resp); !ok || got <= in-2*time.Second || got > in {
+			t.Errorf("retryAfter(in %v) = %v, %v", in, got, ok)
+		}
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	tests := []struct {
+		method string
+		status int
+		want   bool
+	}{
+		{http.MethodGet, http.StatusTooManyRequests, true},
+		{http.MethodPost, http.StatusTooManyRequests, true},
+		{http.MethodGet, http.StatusBadGateway, true},
+		{http.MethodPut, http.StatusServiceUnavailable, true},
+		{http.MethodDelete, http.StatusGatewayTimeout, true},
+		{http.MethodPost, http.StatusInternalServerError, false},
+		{http.MethodPost, http.StatusBadGateway, false},
+		{http.MethodGet, http.StatusBadRequest, false},
+		{http.MethodGet, http.StatusNotFound, false},
+		{http.MethodGet, http.StatusNotImplemented, false},
+	}
+	for _, tt := range tests {
+		if got := shouldRetry(tt.method, tt.status); got != tt.want {
+			t.Errorf("shouldRetry(%s, %d) = %v, want %v", tt.method, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statuses   []int  // returned in turn, the last one from then on
+		retryAfter string // sent with the statuses, "0" when empty
+		wantErr    int    // status of the returned error, 0 for success
+		requests   int
+	}{
+		{name: "GET after 503", method: http.MethodGet, statuses: []int{503, 503, 200}, requests: 3},
+		{name: "GET gives up", method: http.MethodGet, statuses: []int{502}, wantErr: 502, requests: defaultMaxRetries + 1},
+		{name: "POST after 429", method: http.MethodPost, statuses: []int{429, 200}, requests: 2},
+		{name: "GET not when told to wait long", method: http.MethodGet, statuses: []int{429, 200}, retryAfter: "3600", wantErr: 429, requests: 1},
+		{name: "GET when told to wait a little", method: http.MethodGet, statuses: []int{429, 200}, retryAfter: "1", requests: 2},
+		{name: "POST not after 502", method: http.MethodPost, statuses: []int{502, 200}, wantErr: 502, requests: 1},
+		{name: "GET not after 400", method: http.MethodGet, statuses: []int{400, 200}, wantErr: 400, requests: 1},
+		{name: "DELETE gone after 502", method: http.MethodDelete, statuses: []int{502, 404}, requests: 2},
+		{name: "DELETE not found", method: http.MethodDelete, statuses: []int{404}, wantErr: 404, requests: 1},
+		{name: "DELETE not found after 429", method: http.MethodDelete, statuses: []int{429, 404}, wantErr: 404, requests: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newTestClient(t, func(n int, r *http.Request) testReply {
+				if r.Method != tt.method {
+					t.Errorf("method = %s", r.Method)
+				}
+				// Retry-After keeps the test fast.
+				return testReply{status: tt.statuses[min(n, len(tt.statuses)-1)], header: map[string]string{"Retry-After": cmp.Or(tt.retryAfter, "0")}, body: "{}"}
+			})
+
+			err := client.do(context.Background(), tt.method, "/rest/api/2/thing", nil, nil, nil)
+			var jiraErr *JiraError
+			switch {
+			case tt.wantErr == 0 && err != nil:
+				t.Errorf("error = %v", err)
+			case tt.wantErr != 0 && (!errors.As(err, &jiraErr) || jiraErr.StatusCode != tt.wantErr):
+				t.Errorf("error = %v, want status %d", err, tt.wantErr)
+			}
+			if int(requests.Load()) != tt.requests {
+				t.Errorf("%d requests, want %d", requests.Load(), tt.requests)
+			}
+		})
+	}
+}
+
+func TestRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	client, _ := newTestClient(t, func(n int, r *http.Request) testReply {
+		cancel()
+		return testReply{status: http.StatusTooManyRequests, header: map[string]string{"Retry-After": "30"}}
+	})
+	start := time.Now()
+	if err := client.Get(ctx, "/rest/api/2/myself", nil, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("error = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %v to give up", elapsed)
+	}
+}
+
+func TestTransportErrors(t *testing.T) {
+	t.Run("refused", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		client := &JiraClient{BaseURL: server.URL, HTTPClient: server.Client(), MaxRetries: 1}
+		server.Close()
+
+		// Nothing reached Jira, so even a POST is retried.
+		err := client.Post(context.Background(), "/rest/api/2/issue", map[string]string{}, nil)
+		if err == nil || !notConnected(err) || !isUnreachable(err) {
+			t.Errorf("error = %v, want a connection error", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		client, requests := newTestClient(t, func(n int, r *http.Request) testReply {
+			<-release
+			return testReply{}
+		})
+		defer close(release)
+		client.HTTPClient.Timeout = 50 * time.Millisecond
+
+		err := client.Put(context.Background(), "/rest/api/2/issue/TEST-1/assignee", map[string]string{}, nil)
+		if err == nil || notConnected(err) || isUnreachable(err) {
+			t.Errorf("error = %v, want an unretried timeout", err)
+		}
+		if got := describeError(err); !strings.Contains(got, "did not answer in time") {
+			t.Errorf("describeError = %q", got)
+		}
+		if n := requests.Load(); n != 1 {
+			t.Errorf("%d requests, want 1", n)
+		}
+	})
+}
+
+func TestNewJiraError(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		retryAfter  string
+		body        string
+		wantMessage string
+		wantError   string
+	}{
+		{
+			name:        "error messages",
+			status:      400,
+			body:        `{"errorMessages":["Error in the JQL Query: bad"],"errors":{}}`,
+			wantMessage: "Error in the JQL Query: bad",
+			wantError:   "400 Bad Request: Error in the JQL Query: bad (request ID abc)",
+		},
+		{
+			name:        "field errors",
+			status:      400,
+			body:        `{"errorMessages":[],"errors":{"summary":"required","assignee":"unknown"}}`,
+			wantMessage: "assignee: unknown; summary: required",
+		},
+		{
+			name:        "html",
+			status:      502,
+			body:        "<html>Bad gateway</html>\n",
+			wantMessage: "Jira is having problems (502 Bad Gateway), try again later.",
+			wantError:   "502 Bad Gateway: <html>Bad gateway</html> (request ID abc)",
+		},
+		{
+			name:        "empty",
+			status:      401,
+			wantMessage: "Authentication failed, check your email and API token.",
+		},
+		{
+			name:        "rate limited",
+			status:      429,
+			retryAfter:  "90",
+			wantMessage: "Jira is rate limiting requests, try again in 1m30s.",
+		},
+		{
+			name:        "not an error document",
+			status:      404,
+			body:        `{"message":"gone"}`,
+			wantMessage: "Not found, or you don't have permission to see it.",
+			wantError:   `404 Not Found: {"message":"gone"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, _ := newTestClient(t, func(n int, r *http.Request) testReply {
+				header := map[string]string{"X-AREQUESTID": "abc"}
+				if tt.retryAfter != "" {
+					header["Retry-After"] = tt.retryAfter
+				}
+				return testReply{status: tt.status, header: header, body: tt.body}
+			})
+			client.MaxRetries = 0
+
+			err := client.Get(context.Background(), "/rest/api/2/issue/TEST-1", url.Values{"fields": {"summary"}}, nil)
+			var jiraErr *JiraError
+			if !errors.As(err, &jiraErr) {
+				t.Fatalf("error = %v, want a *JiraError", err)
+			}
+			if jiraErr.StatusCode != tt.status || jiraErr.RequestID != "abc" || jiraErr.Method != http.MethodGet ||
+				!strings.HasSuffix(jiraErr.URL, "/rest/api/2/issue/TEST-1?fields=summary") {
+				t.Errorf("error = %+v", jiraErr)
+			}
+			if got := jiraErr.Message(); got != tt.wantMessage {
+				t.Errorf("Message() = %q, want %q", got, tt.wantMessage)
+			}
+			if got := describeError(err); got != tt.wantMessage+" (request ID abc)" {
+				t.Errorf("describeError = %q", got)
+			}
+			if tt.wantError != "" && !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("Error() = %q, want it to contain %q", err.Error(), tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// JiraError is returned for any non-2xx response from Jira. It carries the
+// messages Jira put in its standard error body so that callers can show
+// something more useful than the raw response.
+type JiraError struct {
+	StatusCode    int
+	Status        string
+	Method        string
+	URL           string
+	ErrorMessages []string          // general messages ("errorMessages")
+	Errors        map[string]string // per-field messages ("errors")
+	RequestID     string            // X-AREQUESTID, quoted by Atlassian support
+	Body          string            // raw body when it is not a Jira error document
+	RetryAfter    time.Duration     // how long Jira asked to wait before retrying, if it did
+}
+
+// newJiraError builds a JiraError from a response whose body has already been read.
+func newJiraError(resp *http.Response, body []byte) *JiraError {
+	jiraErr := &JiraError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  resp.Header.Get("X-AREQUESTID"),
+	}
+	jiraErr.RetryAfter, _ = retryAfter(resp)
+	if resp.Request != nil {
+		jiraErr.Method = resp.Request.Method
+		jiraErr.URL = resp.Request.URL.Redacted()
+	}
+
+	var payload struct {
+		ErrorMessages []string          `json:"errorMessages"`
+		Errors        map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil && (len(payload.ErrorMessages) > 0 || len(payload.Errors) > 0) {
+		jiraErr.ErrorMessages = payload.ErrorMessages
+		jiraErr.Errors = payload.Errors
+	} else {
+		jiraErr.Body = strings.TrimSpace(string(body))
+	}
+	return jiraErr
+}
+
+func (e *JiraError) Error() string {
+	msg := fmt.Sprintf("Jira API %s %s returned %s", e.Method, e.URL, e.Status)
+	if details := e.details(); details != "" {
+		msg += ": " + details
+	} else if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID %s)", e.RequestID)
+	}
+	return msg
+}
+
+// Message returns a short, human readable description suitable for the
+// status bar.
+func (e *JiraError) Message() string {
+	if details := e.details(); details != "" {
+		return details
+	}
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return "Authentication failed, check your email and API token."
+	case http.StatusForbidden:
+		return "You don't have permission to do that."
+	case http.StatusNotFound:
+		return "Not found, or you don't have permission to see it."
+	case http.StatusTooManyRequests:
+		if e.RetryAfter > 0 {
+			return fmt.Sprintf("Jira is rate limiting requests, try again in %s.", e.RetryAfter.Round(time.Second))
+		}
+		return "Jira is rate limiting requests, try again in a moment."
+	}
+	if e.StatusCode >= 500 {
+		return fmt.Sprintf("Jira is having problems (%s), try again later.", e.Status)
+	}
+	return fmt.Sprintf("Jira returned %s.", e.Status)
+}
+
+// details joins Jira's errorMessages and field errors.
+func (e *JiraError) details() string {
+	parts := append([]string(nil), e.ErrorMessages...)
+	fields := make([]string, 0, len(e.Errors))
+	for field := range e.Errors {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		parts = append(parts, fmt.Sprintf("%s: %s", field, e.Errors[field]))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// describeError returns the text to show to the user for err, preferring
+// Jira's own message when err wraps a *JiraError.
+func describeError(err error) string {
+	var jiraErr *JiraError
+	if errors.As(err, &jiraErr) {
+		msg := jiraErr.Message()
+		if jiraErr.RequestID != "" {
+			msg += fmt.Sprintf(" (request ID %s)", jiraErr.RequestID)
+		}
+		return msg
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "Jira did not answer in time, the change may or may not have been made."
+	}
+	return err.Error()
+}
+
+// isUnreachable reports whether err means that Jira could not be reached at
+// all, as opposed to Jira rejecting the request. A timeout does not count:
+// the request may have reached Jira and been carried out, so it must not be
+// queued and sent again.
+func isUnreachable(err error) bool {
+	return !errors.Is(err, context.Canceled) && notConnected(err)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// --- Pagination ---
+//
+// Jira uses three different paging schemes:
+//   - offset based (startAt/maxResults/total) on the classic /search endpoint,
+//   - isLast based on the agile API (/rest/agile/1.0/...),
+//   - token based (nextPageToken) on the newer /search/jql endpoint.
+// The helpers below hide these behind a single "fetch everything" call that
+// optionally reports each page as soon as it arrives.
+
+const defaultPageSize = 100
+
+// agilePage is the envelope used by paginated /rest/agile/1.0 endpoints.
+type agilePage[T any] struct {
+	StartAt    int  `json:"startAt"`
+	MaxResults int  `json:"maxResults"`
+	IsLast     bool `json:"isLast"`
+	Values     []T  `json:"values"`
+}
+
+// remaining returns how many more items may be fetched when at most limit
+// are wanted, or -1 if limit is 0 and there is no cap.
+func remaining(limit, fetched int) int {
+	if limit <= 0 {
+		return -1
+	}
+	return limit - fetched
+}
+
+// pageSize returns the maxResults to request for the next page.
+func pageSize(limit, fetched int) int {
+	if left := remaining(limit, fetched); left >= 0 && left < defaultPageSize {
+		return left
+	}
+	return defaultPageSize
+}
+
+// appendCapped appends page to items, dropping anything beyond limit. It
+// returns the new slice, the part of page that was kept and whether the
+// limit has been reached.
+func appendCapped[T any](limit int, items, page []T) ([]T, []T, bool) {
+	if left := remaining(limit, len(items)); left >= 0 && len(page) >= left {
+		page = page[:left]
+		return append(items, page...), page, true
+	}
+	return append(items, page...), page, false
+}
+
+// fetchAgileValues follows isLast pagination on an agile API endpoint.
+func fetchAgileValues[T any](ctx context.Context, c *JiraClient, path string, query url.Values, onPage func([]T)) ([]T, error) {
+	var items []T
+	for {
+		params := cloneValues(query)
+		params.Set("startAt", strconv.Itoa(len(items)))
+		params.Set("maxResults", strconv.Itoa(defaultPageSize))
+
+		resp, err := getJSON[agilePage[T]](ctx, c, path, params)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, resp.Values...)
+		if onPage != nil && len(resp.Values) > 0 {
+			onPage(resp.Values)
+		}
+		if resp.IsLast || len(resp.Values) == 0 {
+			return items, nil
+		}
+	}
+}
+
+// fetchListPages follows startAt pagination on endpoints that return a bare
+// JSON array, stopping at the first empty page. A short page does not mean
+// the end: Jira Cloud leaves out the users hidden from the caller, so a page
+// may hold fewer items than asked for, and startAt counts them all.
+func fetchListPages[T any](ctx context.Context, c *JiraClient, path string, query url.Values) ([]T, error) {
+	var items []T
+	for startAt := 0; ; startAt += defaultPageSize {
+		params := cloneValues(query)
+		params.Set("startAt", strconv.Itoa(startAt))
+		params.Set("maxResults", strconv.Itoa(defaultPageSize))
+
+		resp, err := getJSON[[]T](ctx, c, path, params)
+		if err != nil {
+			return items, err
+		}
+		if len(resp) == 0 {
+			return items, nil
+		}
+		items = append(items, resp...)
+	}
+}
+
+// searchIssuesByOffset pages through /search using startAt and total, up to
+// limit issues, or all of them when limit is 0.
+func (c *JiraClient) searchIssuesByOffset(ctx context.Context, path string, query url.Values, limit int, onPage func([]Issue)) ([]Issue, error) {
+	var issues []Issue
+	for {
+		params := cloneValues(query)
+		params.Set("startAt", strconv.Itoa(len(issues)))
+		params.Set("maxResults", strconv.Itoa(pageSize(limit, len(issues))))
+
+		resp, err := getJSON[JiraSearchResponse](ctx, c, path, params)
+		if err != nil {
+			return issues, err
+		}
+		var page []Issue
+		var capped bool
+		issues, page, capped = appendCapped(limit, issues, resp.Issues)
+		if onPage != nil && len(page) > 0 {
+			onPage(page)
+		}
+		if len(resp.Issues) == 0 || len(issues) >= resp.Total || capped {
+			return issues, nil
+		}
+	}
+}
+
+// searchIssuesByToken pages through /search/jql using nextPageToken, up to
+// limit issues, or all of them when limit is 0.
+func (c *JiraClient) searchIssuesByToken(ctx context.Context, path string, query url.Values, limit int, onPage func([]Issue)) ([]Issue, error) {
+	var issues []Issue
+	token := ""
+	for {
+		params := cloneValues(query)
+		params.Set("maxResults", strconv.Itoa(pageSize(limit, len(issues))))
+		if token != "" {
+			params.Set("nextPageToken", token)
+		}
+
+		resp, err := getJSON[JiraSearchResponse](ctx, c, path, params)
+		if err != nil {
+			return issues, err
+		}
+		var page []Issue
+		var capped bool
+		issues, page, capped = appendCapped(limit, issues, resp.Issues)
+		if onPage != nil && len(page) > 0 {
+			onPage(page)
+		}
+		token = resp.NextPageToken
+		if resp.IsLast || token == "" || capped {
+			return issues, nil
+		}
+	}
+}
+
+func cloneValues(v url.Values) url.Values {
+	out := url.Values{}
+	for key, values := range v {
+		out[key] = append([]string(nil), values...)
+	}
+	return out
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// jqlBar is the ":" command bar used to run arbitrary JQL. Up and Down walk
+// through the query history and Tab cycles through completions. The line
+// below the input shows completion hints, or errors from Jira.
+type jqlBar struct {
+	layout    *tview.Flex
+	input     *tview.InputField
+	hintView  *tview.TextView
+	history   *jqlHistory
+	completer *jqlCompleter
+	position  int    // index into history.entries, len(entries) for the draft
+	draft     string // text typed before browsing the history
+
+	// Tab cycling state: the text before the completed token, the candidates
+	// and the one currently inserted. completed is the text the last
+	// completion produced; any other edit starts a new cycle.
+	base       string
+	candidates []string
+	index      int
+	completed  string
+}
+
+func newJQLBar(history *jqlHistory, completer *jqlCompleter) *jqlBar {
+	b := &jqlBar{history: history, completer: completer}
+	b.input = tview.NewInputField().SetLabel("JQL: ")
+	b.input.SetFieldBackgroundColor(tcell.ColorDefault)
+	b.input.SetFieldTextColor(tcell.ColorWhite)
+	b.input.SetLabelColor(tcell.ColorAqua)
+	b.hintView = tview.NewTextView().SetDynamicColors(true)
+	b.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(b.input, 1, 0, true).
+		AddItem(b.hintView, 1, 0, false)
+	b.layout.SetBorder(true).SetTitle("Run JQL (Enter to run, Tab to complete, ↑/↓ history, Esc to close)")
+	b.layout.SetBorderColor(tcell.ColorAqua)
+
+	b.input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			b.browse(-1)
+			return nil
+		case tcell.KeyDown:
+			b.browse(1)
+			return nil
+		case tcell.KeyTab:
+			b.cycle(1)
+			return nil
+		case tcell.KeyBacktab:
+			b.cycle(-1)
+			return nil
+		}
+		return event
+	})
+	b.input.SetChangedFunc(func(text string) {
+		if text != b.completed {
+			b.candidates = nil
+			b.showHints()
+		}
+	})
+	completer.ready = func() {
+		if b.candidates == nil && b.input.HasFocus() {
+			b.showHints()
+		}
+	}
+	return b
+}
+
+// reset prepares the bar to be shown with jql as the initial text.
+func (b *jqlBar) reset(jql string) {
+	b.candidates, b.completed = nil, ""
+	b.input.SetText(jql)
+	b.hintView.Clear()
+	b.position = len(b.history.entries)
+	b.draft = jql
+}
+
+func (b *jqlBar) showError(message string) {
+	b.hintView.SetText("[red]" + escapeTags(message))
+}
+
+// showHints lists the completions for the current text, highlighting the
+// one inserted by Tab, if any.
+func (b *jqlBar) showHints() {
+	candidates, current := b.candidates, b.index
+	if candidates == nil {
+		_, candidates = b.completer.complete(b.input.GetText())
+		current = -1
+	}
+	if len(candidates) == 0 {
+		b.hintView.Clear()
+		return
+	}
+	var hints strings.Builder
+	hints.WriteString("[gray]Tab:[-]")
+	for i, c := range candidates {
+		if i == current {
+			hints.WriteString(" [black:aqua]" + escapeTags(c) + "[-:-]")
+		} else {
+			hints.WriteString(" " + escapeTags(c))
+		}
+	}
+	b.hintView.SetText(hints.String())
+}
+
+// cycle replaces the token being typed with the next (delta 1) or previous
+// (delta -1) completion.
+func (b *jqlBar) cycle(delta int) {
+	text := b.input.GetText()
+	if b.candidates == nil || text != b.completed {
+		start, candidates := b.completer.complete(text)
+		if len(candidates) == 0 {
+			return
+		}
+		b.base, b.candidates, b.index = text[:start], candidates, -1
+		if delta < 0 {
+			b.index = 0
+		}
+	}
+	n := len(b.candidates)
+	b.index = (b.index + delta + n) % n
+	b.completed = b.base + b.candidates[b.index]
+	b.input.SetText(b.completed)
+	b.showHints()
+}
+
+// browse moves through the history by delta entries.
+func (b *jqlBar) browse(delta int) {
+	entries := b.history.entries
+	next := b.position + delta
+	if next < 0 || next > len(entries) {
+		return
+	}
+	if b.position == len(entries) {
+		b.draft = b.input.GetText()
+	}
+	b.position = next
+	if next == len(entries) {
+		b.input.SetText(b.draft)
+	} else {
+		b.input.SetText(entries[next])
+	}
+}
@@ -0,0 +1,363 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+const maxJQLCandidates = 20
+
+// jqlCompleteRetryDelay is how long the completer stops asking Jira after a
+// failed fetch, rather than trying again at every keystroke.
+const jqlCompleteRetryDelay = 30 * time.Second
+
+var (
+	jqlDefaultOperators = []string{"=", "!=", "~", "!~", ">", ">=", "<", "<=", "in", "not in", "is", "is not", "was", "was in", "was not", "changed"}
+	jqlKeywords         = []string{"AND", "OR", "ORDER BY"}
+	jqlOrderKeywords    = []string{"ASC", "DESC"}
+)
+
+type jqlCompletionKind int
+
+const (
+	completeField jqlCompletionKind = iota
+	completeOrderField
+	completeOperator
+	completeValue
+	completeKeyword
+	completeOrderKeyword
+)
+
+// jqlToken is a word, quoted string, operator or punctuation of a query
+// together with its byte offset.
+type jqlToken struct {
+	text  string
+	start int
+}
+
+// tokenizeJQL splits a (possibly incomplete) query into tokens.
+func tokenizeJQL(text string) []jqlToken {
+	var tokens []jqlToken
+	for i := 0; i < len(text); {
+		c := text[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+			continue
+		case c == '"' || c == '\'':
+			i++
+			for i < len(text) && text[i] != c {
+				if text[i] == '\\' {
+					i++
+				}
+				i++
+			}
+			i = min(i+1, len(text))
+		case strings.IndexByte("=!<>~", c) >= 0:
+			for i < len(text) && strings.IndexByte("=!<>~", text[i]) >= 0 {
+				i++
+			}
+		case strings.IndexByte("(),", c) >= 0:
+			i++
+		default:
+			for i < len(text) && strings.IndexByte(" \t\n=!<>~(),\"'", text[i]) < 0 {
+				i++
+			}
+		}
+		tokens = append(tokens, jqlToken{text: text[start:i], start: start})
+	}
+	return tokens
+}
+
+func isJQLConnector(word string) bool {
+	switch strings.ToLower(word) {
+	case "and", "or", "not", "(":
+		return true
+	}
+	return false
+}
+
+func isJQLOperator(word string) bool {
+	switch strings.ToLower(word) {
+	case "=", "!=", "~", "!~", ">", ">=", "<", "<=", "in", "is", "was", "changed":
+		return true
+	}
+	return false
+}
+
+// jqlCompletionContext works out what may follow the tokens prev and, for
+// operators and values, which field they apply to.
+func jqlCompletionContext(prev []string) (jqlCompletionKind, string) {
+	n := len(prev)
+	if n == 0 {
+		return completeField, ""
+	}
+	at := func(i int) string { // i-th token from the end, lower case
+		if i > n {
+			return ""
+		}
+		return strings.ToLower(prev[n-i])
+	}
+	// fieldBefore returns the field an operator ending at token i applies to,
+	// skipping the "not" of "not in" / "is not" / "was not".
+	fieldBefore := func(i int) string {
+		if at(i+1) == "not" || at(i+1) == "is" || at(i+1) == "was" {
+			i++
+		}
+		if i+1 > n {
+			return ""
+		}
+		return prev[n-i-1]
+	}
+
+	inOrderBy := false
+	for i := 0; i+1 < n; i++ {
+		if strings.EqualFold(prev[i], "order") && strings.EqualFold(prev[i+1], "by") {
+			inOrderBy = true
+		}
+	}
+
+	switch last := at(1); {
+	case last == "order":
+		return completeKeyword, ""
+	case last == "by":
+		return completeOrderField, ""
+	case last == ",":
+		if inOrderBy {
+			return completeOrderField, ""
+		}
+		// Inside an "in (...)" list: find the opening parenthesis.
+		for i := 2; i <= n; i++ {
+			if at(i) == "(" {
+				return completeValue, fieldBefore(i + 1)
+			}
+		}
+		return completeValue, ""
+	case last == "(" && isJQLOperator(at(2)):
+		return completeValue, fieldBefore(2)
+	case last == "not" && (at(2) == "is" || at(2) == "was"):
+		return completeValue, fieldBefore(1)
+	case last == "not" && n >= 2 && !isJQLConnector(at(2)):
+		return completeOperator, prev[n-2]
+	case isJQLOperator(last):
+		return completeValue, fieldBefore(1)
+	case isJQLConnector(last):
+		return completeField, ""
+	case inOrderBy:
+		return completeOrderKeyword, ""
+	case n == 1 || isJQLConnector(at(2)):
+		return completeOperator, prev[n-1]
+	}
+	return completeKeyword, ""
+}
+
+// jqlCompleter suggests completions for the JQL bar using Jira's autocomplete
+// endpoints. Results are cached for the session. Its methods must be called
+// from the UI goroutine; ready is called there when fetched data arrives.
+type jqlCompleter struct {
+	ctx    context.Context
+	app    *tview.Application
+	client *JiraClient
+	ready  func()
+
+	data        *JQLAutocompleteData
+	loadingData bool
+	suggestions map[string][]string // by field + "\x00" + prefix
+	pending     map[string]bool
+	failedAt    time.Time // when a fetch last failed
+}
+
+func newJQLCompleter(ctx context.Context, app *tview.Application, client *JiraClient) *jqlCompleter {
+	return &jqlCompleter{
+		ctx:         ctx,
+		app:         app,
+		client:      client,
+		suggestions: map[string][]string{},
+		pending:     map[string]bool{},
+	}
+}
+
+// complete returns the candidates for the token being typed at the end of
+// text and the offset at which that token starts.
+func (c *jqlCompleter) complete(text string) (int, []string) {
+	tokens := tokenizeJQL(text)
+	start, prefix := len(text), ""
+	if n := len(tokens); n > 0 {
+		last := tokens[n-1]
+		if last.start+len(last.text) == len(text) && strings.IndexByte("(),", last.text[0]) < 0 {
+			start, prefix = last.start, last.text
+			tokens = tokens[:n-1]
+		}
+	}
+	prev := make([]string, len(tokens))
+	for i, t := range tokens {
+		prev[i] = t.text
+	}
+
+	kind, field := jqlCompletionContext(prev)
+	var candidates []string
+	switch kind {
+	case completeField, completeOrderField:
+		candidates = c.fieldNames(kind == completeOrderField)
+	case completeOperator:
+		candidates = c.operators(field)
+		// "not" was already typed as part of "not in".
+		if len(prev) > 0 && strings.EqualFold(prev[len(prev)-1], "not") {
+			candidates = []string{"in"}
+		}
+	case completeValue:
+		candidates = c.values(field, prefix)
+	case completeKeyword:
+		candidates = jqlKeywords
+		if len(prev) > 0 && strings.EqualFold(prev[len(prev)-1], "order") {
+			candidates = []string{"BY"}
+		}
+	case completeOrderKeyword:
+		candidates = append(append([]string(nil), jqlOrderKeywords...), ",")
+	}
+	return start, filterByPrefix(candidates, prefix)
+}
+
+func filterByPrefix(candidates []string, prefix string) []string {
+	prefix = strings.ToLower(strings.Trim(prefix, `"'`))
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(strings.Trim(candidate, `"'`)), prefix) {
+			matches = append(matches, candidate)
+			if len(matches) == maxJQLCandidates {
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// backingOff reports whether a fetch failed too recently to try again.
+func (c *jqlCompleter) backingOff() bool {
+	return time.Since(c.failedAt) < jqlCompleteRetryDelay
+}
+
+// loadData fetches the autocomplete data once per session.
+func (c *jqlCompleter) loadData() {
+	if c.data != nil || c.loadingData || c.backingOff() {
+		return
+	}
+	c.loadingData = true
+	go func() {
+		data, err := c.client.FetchJQLAutocompleteData(c.ctx)
+		c.app.QueueUpdateDraw(func() {
+			c.loadingData = false
+			if err != nil {
+				c.failedAt = time.Now()
+				return
+			}
+			c.data = &data
+			if c.ready != nil {
+				c.ready()
+			}
+		})
+	}()
+}
+
+func (c *jqlCompleter) fieldNames(orderable bool) []string {
+	c.loadData()
+	if c.data == nil {
+		return nil
+	}
+	var names []string
+	for _, f := range c.data.VisibleFieldNames {
+		if !orderable || f.Orderable == "true" {
+			names = append(names, f.Value)
+		}
+	}
+	return names
+}
+
+// field returns the operators and types of a field as typed in a query.
+func (c *jqlCompleter) field(name string) (operators, types []string) {
+	c.loadData()
+	if c.data == nil {
+		return nil, nil
+	}
+	name = strings.Trim(name, `"'`)
+	for _, f := range c.data.VisibleFieldNames {
+		if strings.EqualFold(strings.Trim(f.Value, `"'`), name) || strings.EqualFold(f.DisplayName, name) {
+			return f.Operators, f.Types
+		}
+	}
+	return nil, nil
+}
+
+func (c *jqlCompleter) operators(field string) []string {
+	if operators, _ := c.field(field); len(operators) > 0 {
+		return operators
+	}
+	return jqlDefaultOperators
+}
+
+// values returns the known values of field starting with prefix together
+// with the functions that apply to it, fetching suggestions in the background
+// the first time a prefix is seen. Failed fetches are not cached and are
+// tried again once the completer stops backing off.
+func (c *jqlCompleter) values(field, prefix string) []string {
+	if field == "" {
+		return nil
+	}
+	bare := strings.Trim(prefix, `"'`)
+	key := strings.ToLower(field) + "\x00" + strings.ToLower(bare)
+	values, ok := c.suggestions[key]
+	if !ok && !c.pending[key] && !c.backingOff() {
+		c.pending[key] = true
+		go func() {
+			fetched, err := c.client.FetchJQLSuggestions(c.ctx, strings.Trim(field, `"'`), bare)
+			c.app.QueueUpdateDraw(func() {
+				delete(c.pending, key)
+				if err != nil {
+					c.failedAt = time.Now()
+					return
+				}
+				c.suggestions[key] = fetched
+				if c.ready != nil {
+					c.ready()
+				}
+			})
+		}()
+	}
+
+	var candidates []string
+	for _, v := range values {
+		candidates = append(candidates, quoteJQLValue(v))
+	}
+	_, types := c.field(field)
+	if c.data != nil {
+		for _, fn := range c.data.VisibleFunctionNames {
+			if typesOverlap(fn.Types, types) {
+				candidates = append(candidates, fn.Value)
+			}
+		}
+	}
+	return candidates
+}
+
+func typesOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// quoteJQLValue quotes a value unless it is a single plain word.
+func quoteJQLValue(v string) string {
+	if v != "" && strings.IndexAny(v, " \t\"'=!<>~(),") < 0 {
+		return v
+	}
+	return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func TestJQLCompleterBacksOff(t *testing.T) {
+	client, requests := newTestClient(t, func(n int, r *http.Request) testReply {
+		return testReply{status: http.StatusBadRequest, body: map[string][]string{"errorMessages": {"no"}}}
+	})
+	app := tview.NewApplication()
+	runTestApp(t, app, tview.NewBox())
+	c := newJQLCompleter(context.Background(), app, client)
+
+	// complete types text in the JQL bar and waits for the fetches it starts.
+	complete := func(text string) {
+		t.Helper()
+		app.QueueUpdate(func() { c.complete(text) })
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+			var busy bool
+			app.QueueUpdate(func() { busy = c.loadingData || len(c.pending) > 0 })
+			if !busy {
+				return
+			}
+		}
+		t.Fatalf("the fetches started by %q did not end", text)
+	}
+	complete("pro")
+	if n := requests.Load(); n != 1 {
+		t.Fatalf("%d requests for the autocomplete data, want 1", n)
+	}
+	for _, text := range []string{"proj", "project = A", "project = AB", "project = ABC"} {
+		complete(text)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("%d requests after a failure, want none until the delay is over", n)
+	}
+
+	app.QueueUpdate(func() { c.failedAt = time.Now().Add(-jqlCompleteRetryDelay) })
+	complete("project = ABCD")
+	if n := requests.Load(); n != 3 {
+		t.Errorf("%d requests once the delay is over, want the data and the suggestions fetched again", n)
+	}
+}
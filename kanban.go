@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// kanbanColumn is a column of the kanban view. A column matches issues either
+// by status ID (board columns) or by status name (derived columns).
+type kanbanColumn struct {
+	name       string
+	statusIDs  map[string]bool
+	statusName string
+	issues     []Issue
+}
+
+// matches reports whether an issue in the given status belongs to the column.
+func (c *kanbanColumn) matches(status Status) bool {
+	if c.statusIDs != nil {
+		return c.statusIDs[status.ID]
+	}
+	return strings.EqualFold(c.statusName, status.Name)
+}
+
+// boardKanbanColumns turns a board column configuration into kanban columns.
+func boardKanbanColumns(config []BoardColumn) []kanbanColumn {
+	var columns []kanbanColumn
+	for _, bc := range config {
+		column := kanbanColumn{name: bc.Name, statusIDs: map[string]bool{}}
+		for _, s := range bc.Statuses {
+			column.statusIDs[s.ID] = true
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// statusKanbanColumns derives one column per status found in issues, ordered
+// by status category (to do, in progress, done) and then by name.
+func statusKanbanColumns(issues []Issue) []kanbanColumn {
+	seen := map[string]Status{}
+	for _, issue := range issues {
+		seen[strings.ToLower(issue.Fields.Status.Name)] = issue.Fields.Status
+	}
+	statuses := make([]Status, 0, len(seen))
+	for _, s := range seen {
+		statuses = append(statuses, s)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		ci, cj := statusCategoryRank(statuses[i]), statusCategoryRank(statuses[j])
+		if ci != cj {
+			return ci < cj
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	columns := make([]kanbanColumn, len(statuses))
+	for i, s := range statuses {
+		columns[i] = kanbanColumn{name: s.Name, statusName: s.Name}
+	}
+	return columns
+}
+
+// statusCategoryRank orders statuses to do < in progress < done.
+func statusCategoryRank(s Status) int {
+	if s.StatusCategory == nil {
+		return 1
+	}
+	switch s.StatusCategory.Key {
+	case "new":
+		return 0
+	case "indeterminate":
+		return 1
+	case "done":
+		return 2
+	}
+	return 1
+}
+
+// kanbanView shows issues as cards in status columns. Left/Right move between
+// columns, Shift+Left/Right (or H/L) move the selected card.
+type kanbanView struct {
+	app     *tview.Application
+	layout  *tview.Flex
+	board   *tview.Flex
+	columns []kanbanColumn
+	lists   []*tview.List
+	focused int
+
+	onMove  func(issue Issue, target *kanbanColumn)
+	onOpen  func(issue Issue)
+	onClose func()
+}
+
+func newKanbanView(app *tview.Application, statusTextView *tview.TextView) *kanbanView {
+	k := &kanbanView{app: app, board: tview.NewFlex()}
+	k.layout = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(k.board, 0, 1, true).
+		AddItem(statusTextView, 3, 0, false)
+	k.board.SetInputCapture(k.handleKey)
+	return k
+}
+
+// render lays out issues in columns. columns defines the column order; issues
+// that match none of them end up in an extra "Other" column. The selected card
+// is kept when possible.
+func (k *kanbanView) render(title string, columns []kanbanColumn, issues []Issue) {
+	selectedKey := ""
+	if issue, ok := k.selected(); ok {
+		selectedKey = issue.Key
+	}
+	hadFocus := k.hasFocus()
+	defer func() {
+		if hadFocus {
+			k.focus()
+		}
+	}()
+
+	k.columns = make([]kanbanColumn, len(columns))
+	copy(k.columns, columns)
+	var other []Issue
+	for _, issue := range issues {
+		placed := false
+		for i := range k.columns {
+			if k.columns[i].matches(issue.Fields.Status) {
+				k.columns[i].issues = append(k.columns[i].issues, issue)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			other = append(other, issue)
+		}
+	}
+	if len(other) > 0 {
+		k.columns = append(k.columns, kanbanColumn{name: "Other", statusIDs: map[string]bool{}, issues: other})
+	}
+
+	k.board.Clear()
+	k.board.SetBorder(true).SetTitle(title + " (←/→ columns, Shift+←/→ move card, Enter open, v close)")
+	k.lists = nil
+	for ci, column := range k.columns {
+		list := tview.NewList()
+		list.SetBorder(true).SetTitle(fmt.Sprintf("%s (%d)", escapeTags(column.name), len(column.issues)))
+		list.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+		list.SetSecondaryTextColor(tcell.ColorGray)
+		list.SetSelectedFocusOnly(true)
+		for ii, issue := range column.issues {
+			assignee := "Unassigned"
+			if issue.Fields.Assignee != nil {
+				assignee = issue.Fields.Assignee.DisplayName
+			}
+			list.AddItem(fmt.Sprintf("%s%s[white] %s", getIssueTypeColor(issue.Fields.IssueType.Name), escapeTags(issue.Key), escapeTags(issue.Fields.Summary)), "  "+escapeTags(assignee), 0, nil)
+			if issue.Key == selectedKey {
+				k.focused = ci
+				list.SetCurrentItem(ii)
+			}
+		}
+		list.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+			if issue, ok := k.selected(); ok && k.onOpen != nil {
+				k.onOpen(issue)
+			}
+		})
+		k.lists = append(k.lists, list)
+		k.board.AddItem(list, 0, 1, false)
+	}
+	if len(k.lists) == 0 {
+		k.board.AddItem(tview.NewTextView().SetText("No tickets to show."), 0, 1, false)
+		return
+	}
+	k.focused = min(k.focused, len(k.lists)-1)
+}
+
+// focus gives keyboard focus to the current column.
+func (k *kanbanView) focus() {
+	if len(k.lists) == 0 {
+		k.app.SetFocus(k.board)
+		return
+	}
+	k.app.SetFocus(k.lists[k.focused])
+}
+
+// hasFocus reports whether the board or one of its columns has focus.
+func (k *kanbanView) hasFocus() bool {
+	focused := k.app.GetFocus()
+	if focused == k.board {
+		return true
+	}
+	for _, list := range k.lists {
+		if focused == list {
+			return true
+		}
+	}
+	return false
+}
+
+func (k *kanbanView) selected() (Issue, bool) {
+	if k.focused < 0 || k.focused >= len(k.lists) {
+		return Issue{}, false
+	}
+	column := k.columns[k.focused]
+	index := k.lists[k.focused].GetCurrentItem()
+	if index < 0 || index >= len(column.issues) {
+		return Issue{}, false
+	}
+	return column.issues[index], true
+}
+
+func (k *kanbanView) handleKey(event *tcell.EventKey) *tcell.EventKey {
+	shift := event.Modifiers()&tcell.ModShift != 0
+	switch {
+	case event.Key() == tcell.KeyLeft && shift, event.Rune() == 'H':
+		k.moveCard(-1)
+	case event.Key() == tcell.KeyRight && shift, event.Rune() == 'L':
+		k.moveCard(1)
+	case event.Key() == tcell.KeyLeft, event.Rune() == 'h':
+		k.focusColumn(k.focused - 1)
+	case event.Key() == tcell.KeyRight, event.Rune() == 'l':
+		k.focusColumn(k.focused + 1)
+	case event.Key() == tcell.KeyEscape, event.Rune() == 'v':
+		if k.onClose != nil {
+			k.onClose()
+		}
+	default:
+		return event
+	}
+	return nil
+}
+
+func (k *kanbanView) focusColumn(index int) {
+	if index < 0 || index >= len(k.lists) {
+		return
+	}
+	k.focused = index
+	k.focus()
+}
+
+// moveCard asks for the selected card to be moved to the column at offset
+// direction from the current one.
+func (k *kanbanView) moveCard(direction int) {
+	issue, ok := k.selected()
+	target := k.focused + direction
+	if !ok || target < 0 || target >= len(k.columns) || k.onMove == nil {
+		return
+	}
+	k.onMove(issue, &k.columns[target])
+}
+
+// moveIssueToColumn transitions issue to a status of the target column,
+// asking for transition screen fields when needed.
+func moveIssueToColumn(ctx context.Context, app *tview.Application, client *JiraClient, box *outbox, issue Issue, target *kanbanColumn, returnTo func(), updateStatusFunc func(message string, isError bool), onTransitioned func(Issue)) {
+	targetName := target.name
+	go func() {
+		updateStatusFunc(fmt.Sprintf("Moving %s to %s...", issue.Key, targetName), false)
+		transitions, err := fetchTransitions(ctx, client, issue)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				updateStatusFunc(fmt.Sprintf("Error fetching transitions: %s", describeError(err)), true)
+				return
+			}
+			for _, t := range transitions {
+				if !target.matches(t.To) {
+					continue
+				}
+				if needsTransitionScreen(t) {
+					showTransitionForm(ctx, app, client, box, issue, t, returnTo, updateStatusFunc, onTransitioned)
+					return
+				}
+				runTransition(ctx, app, client, box, issue, t, nil, "", updateStatusFunc, onTransitioned)
+				return
+			}
+			updateStatusFunc(fmt.Sprintf("No transition moves %s from %s to %s.", issue.Key, issue.Fields.Status.Name, targetName), true)
+		})
+	}()
+}
@@ -1,9 +1,18 @@
 package main
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/atotto/clipboard"
 	"github.com/gdamore/tcell/v2"
@@ -79,18 +88,90 @@ func getTcellColorFromStatus(status string) tcell.Color {
 	}
 }
 
-func updateStatus(app *tview.Application, statusTextView *tview.TextView, message string, isError bool) {
+// statusUpdater returns a function showing a message in the status bar. It
+// may be called from any goroutine, event handlers and queued updates
+// included: rather than waiting for the UI goroutine, which deadlocks when
+// called from it, the message is drawn by an update queued from a goroutine
+// of its own. Of messages arriving faster than that only the latest is drawn.
+func statusUpdater(app *tview.Application, statusTextView *tview.TextView) func(message string, isError bool) {
+	var mu sync.Mutex
+	var text string
+	pending := make(chan struct{}, 1)
+	go func() {
+		for range pending {
+			app.QueueUpdateDraw(func() {
+				mu.Lock()
+				defer mu.Unlock()
+				statusTextView.SetText(text)
+			})
+		}
+	}()
+	return func(message string, isError bool) {
+		mu.Lock()
+		text = statusText(message, isError)
+		mu.Unlock()
+		select {
+		case pending <- struct{}{}:
+		default: // an update is queued already and will draw text
+		}
+	}
+}
+
+// statusText formats a message for the status bar, in red for errors.
+func statusText(message string, isError bool) string {
 	color := "green"
 	if isError {
 		color = "red"
 	}
-	statusTextView.SetText(fmt.Sprintf("[%s]%s", color, message))
-	app.Draw()
+	return fmt.Sprintf("[%s]%s", color, escapeTags(message))
+}
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+// spinner animates a busy indicator in the status bar until stopped. Its
+// methods must be called from the UI goroutine.
+type spinner struct {
+	message string
+	stopped bool
+	done    chan struct{}
+}
+
+func startSpinner(app *tview.Application, statusTextView *tview.TextView, message string) *spinner {
+	s := &spinner{message: message, done: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for frame := 0; ; frame++ {
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+			}
+			app.QueueUpdateDraw(func() {
+				if s.stopped {
+					return
+				}
+				statusTextView.SetText(fmt.Sprintf("[yellow]%s [green]%s [gray](Esc to cancel)", spinnerFrames[frame%len(spinnerFrames)], escapeTags(s.message)))
+			})
+		}
+	}()
+	return s
+}
+
+func (s *spinner) SetMessage(message string) {
+	s.message = message
+}
+
+func (s *spinner) Stop() {
+	if !s.stopped {
+		s.stopped = true
+		close(s.done)
+	}
 }
 
 func createIssueList() *tview.List {
 	list := tview.NewList().ShowSecondaryText(false)
-	list.SetBorder(true).SetTitle("Your Jira Tickets (Press Enter for options)")
+	list.SetBorder(true).SetTitle(defaultListTitle)
 	list.SetBorderColor(tcell.ColorGray)
 	list.SetTitleColor(tcell.ColorWhite)
 	list.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
@@ -109,6 +190,8 @@ func createSearchField() *tview.InputField {
 	searchField.SetLabelColor(tcell.ColorAqua)
 	searchField.SetFieldBackgroundColor(tcell.ColorDefault)
 	searchField.SetFieldTextColor(tcell.ColorWhite)
+	searchField.SetPlaceholder("text, s:status, t:type, p:priority, @assignee, #label")
+	searchField.SetPlaceholderTextColor(tcell.ColorGray)
 	return searchField
 }
 
@@ -134,10 +217,22 @@ func createDetailPane() *tview.TextView {
 	return detailPane
 }
 
-func setupActionModal(app *tview.Application, mainFlex *tview.Flex, list *tview.List, displayedIssues *[]Issue, updateStatusFunc func(message string, isError bool)) *tview.Modal {
+// issueAction is an extra button of the action modal.
+type issueAction struct {
+	label string
+	run   func(issue Issue)
+}
+
+func setupActionModal(app *tview.Application, client *JiraClient, mainFlex *tview.Flex, list *tview.List, displayedIssues *[]Issue, updateStatusFunc func(message string, isError bool), actions []issueAction) *tview.Modal {
+	buttons := []string{"Open in Browser", "Generate Branch Name"}
+	for _, action := range actions {
+		buttons = append(buttons, action.label)
+	}
+	buttons = append(buttons, "Cancel")
+
 	modal := tview.NewModal().
 		SetText("What do you want to do?").
-		AddButtons([]string{"Open in Browser", "Generate Branch Name", "Cancel"}).
+		AddButtons(buttons).
 		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
 			app.SetRoot(mainFlex, true).SetFocus(list)
 			if buttonLabel == "Cancel" {
@@ -153,25 +248,69 @@ func setupActionModal(app *tview.Application, mainFlex *tview.Flex, list *tview.
 
 			switch buttonLabel {
 			case "Open in Browser":
-				url := fmt.Sprintf("%s/browse/%s", jiraBaseURL, issue.Key)
-				if err := OpenBrowser(app, url); err != nil {
-					go updateStatusFunc(fmt.Sprintf("Error opening browser: %v", err), true)
+				if err := OpenBrowser(app, client.BrowseURL(issue.Key)); err != nil {
+					updateStatusFunc(fmt.Sprintf("Error opening browser: %v", err), true)
 				} else {
-					go updateStatusFunc(fmt.Sprintf("Opening %s...", issue.Key), false)
+					updateStatusFunc(fmt.Sprintf("Opening %s...", issue.Key), false)
 				}
 			case "Generate Branch Name":
 				branchName := GenerateBranchName(issue)
 				if err := clipboard.WriteAll(branchName); err != nil {
-					go updateStatusFunc(fmt.Sprintf("Error copying to clipboard: %v", err), true)
+					updateStatusFunc(fmt.Sprintf("Error copying to clipboard: %v", err), true)
 				} else {
-					go updateStatusFunc(fmt.Sprintf("Copied to clipboard: %s", branchName), false)
+					updateStatusFunc(fmt.Sprintf("Copied to clipboard: %s", branchName), false)
+				}
+			default:
+				for _, action := range actions {
+					if action.label == buttonLabel {
+						action.run(issue)
+					}
 				}
 			}
 		})
 	return modal
 }
 
-func setupListChangedFunc(list *tview.List, detailPane *tview.TextView, searchField *tview.InputField, statusTextView *tview.TextView, displayedIssues *[]Issue) {
+// formatIssueRow renders an issue as a line of the issue list, highlighting
+// the parts that matched the search and flagging it if a refresh changed it.
+func formatIssueRow(issue Issue, match issueMatch, mark issueMark) string {
+	return fmt.Sprintf("%s%s%s: %s", mark.prefix(), getStatusColor(issue.Fields.Status.Name), highlightMatches(issue.Key, match.key), highlightMatches(issue.Fields.Summary, match.summary))
+}
+
+// formatIssueDetails renders an issue for the detail pane.
+func formatIssueDetails(issue Issue) string {
+	return fmt.Sprintf(`[white]Key: [yellow]%s
+[white]Summary: [yellow]%s
+[white]Status: %s%s[-]
+[white]Issue Type: %s%s[-]
+[white]Assignee: [yellow]%s
+[white]Created: [yellow]%s
+[white]Updated: [yellow]%s
+
+[white]Description:
+[-]%s`,
+		escapeTags(issue.Key),
+		escapeTags(issue.Fields.Summary),
+		getStatusColor(issue.Fields.Status.Name), escapeTags(issue.Fields.Status.Name),
+		getIssueTypeColor(issue.Fields.IssueType.Name), escapeTags(issue.Fields.IssueType.Name),
+		func() string {
+			if issue.Fields.Assignee != nil {
+				return escapeTags(issue.Fields.Assignee.DisplayName)
+			}
+			return "Unassigned"
+		}(),
+		issue.Fields.Created.Format("2006-01-02 15:04"),
+		issue.Fields.Updated.Format("2006-01-02 15:04"),
+		func() string {
+			if description := renderRichText(issue.Fields.Description); strings.TrimSpace(description) != "" {
+				return description
+			}
+			return "[gray]No description."
+		}(),
+	)
+}
+
+func setupListChangedFunc(list *tview.List, detailPane *tview.TextView, searchField *tview.InputField, statusTextView *tview.TextView, displayedIssues *[]Issue, showIssueDetails func(Issue)) {
 	list.SetChangedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
 		if index < 0 || index >= len(*displayedIssues) {
 			detailPane.SetText("Select a ticket to view details.")
@@ -186,53 +325,31 @@ func setupListChangedFunc(list *tview.List, detailPane *tview.TextView, searchFi
 		searchField.SetBorderColor(statusColor)
 		searchField.SetLabelColor(statusColor)
 		statusTextView.SetBorderColor(statusColor)
-		formattedDetails := fmt.Sprintf(`[white]Key: [yellow]%s
-[white]Summary: [yellow]%s
-[white]Status: %s%s[-]
-[white]Issue Type: %s%s[-]
-[white]Assignee: [yellow]%s
-[white]Created: [yellow]%s
-[white]Updated: [yellow]%s
-
-[white]Description:
-[gray]%s
-[white]Comments:
-[gray]%s`,
-			issue.Key,
-			issue.Fields.Summary,
-			getStatusColor(issue.Fields.Status.Name), issue.Fields.Status.Name,
-			getIssueTypeColor(issue.Fields.IssueType.Name), issue.Fields.IssueType.Name,
-			func() string {
-				if issue.Fields.Assignee != nil {
-					return issue.Fields.Assignee.DisplayName
-				}
-				return "Unassigned"
-			}(),
-			issue.Fields.Created.Format("2006-01-02 15:04"),
-			issue.Fields.Updated.Format("2006-01-02 15:04"),
-			func() string {
-				if issue.Fields.Description != nil {
-					return fmt.Sprintf("%v", issue.Fields.Description)
-				}
-				return "No description."
-			}(),
-			func() string {
-				if issue.Fields.Comments != nil && issue.Fields.Comments.Total > 0 {
-					var builder strings.Builder
-					for _, comment := range issue.Fields.Comments.Comments {
-						builder.WriteString(fmt.Sprintf("  - %s (%s): %s\n", comment.Author.DisplayName, comment.Created.Format("2006-01-02"), comment.Body))
-					}
-					return builder.String()
-				}
-				return "No comments."
-			}(),
-		)
-		detailPane.SetText(formattedDetails)
+		showIssueDetails(issue)
 	})
 }
 
-func setupInputCapture(app *tview.Application, searchField *tview.InputField, list *tview.List, modal *tview.Modal) {
+// setupInputCapture installs the global key bindings. shortcuts maps keys
+// to actions that apply while the issue list has focus; ':' also applies in
+// the search box while it is empty, where the app starts.
+func setupInputCapture(app *tview.Application, searchField *tview.InputField, list *tview.List, modal *tview.Modal, cancelFetch func() bool, shortcuts map[rune]func()) {
 	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		onMain := app.GetFocus() == list || app.GetFocus() == searchField
+		if event.Key() == tcell.KeyEscape && onMain && cancelFetch() {
+			return nil
+		}
+		if action, ok := shortcuts[event.Rune()]; ok && event.Key() == tcell.KeyRune && app.GetFocus() == list {
+			action()
+			return nil
+		}
+		// No search starts with a colon, qualifiers have it after their
+		// prefix.
+		if event.Key() == tcell.KeyRune && event.Rune() == ':' && app.GetFocus() == searchField && searchField.GetText() == "" {
+			if action, ok := shortcuts[':']; ok {
+				action()
+				return nil
+			}
+		}
 		if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyDown {
 			if app.GetFocus() == searchField {
 				app.SetFocus(list)
@@ -245,7 +362,7 @@ func setupInputCapture(app *tview.Application, searchField *tview.InputField, li
 				return nil
 			}
 		}
-		if event.Rune() == '/' {
+		if event.Rune() == '/' && onMain {
 			app.SetFocus(searchField)
 			return nil
 		}
@@ -258,20 +375,28 @@ func setupInputCapture(app *tview.Application, searchField *tview.InputField, li
 }
 
 func main() {
-	apiToken := os.Getenv("JIRA_API_TOKEN")
-	if apiToken == "" {
-		fmt.Println("Error: JIRA_API_TOKEN environment variable not set.")
-		os.Exit(1)
+	cfg, err := LoadConfig(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
 	}
-	email := os.Getenv("JIRA_EMAIL")
-	if email == "" {
-		fmt.Println("Error: JIRA_EMAIL environment variable not set.")
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
 		os.Exit(1)
 	}
 
+	if len(cfg.args) > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		status := runCommand(ctx, NewJiraClient(cfg), cfg, cfg.args, os.Stdout, os.Stderr)
+		stop()
+		os.Exit(status)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	app := tview.NewApplication()
 
-	mainFlex := setupMainApp(app, email, apiToken, "assignee = currentUser() ORDER BY created DESC")
+	mainFlex := setupMainApp(ctx, app, NewJiraClient(cfg), cfg)
 	app.SetRoot(mainFlex, true).SetFocus(mainFlex)
 
 	if err := app.Run(); err != nil {
@@ -279,7 +404,7 @@ func main() {
 	}
 }
 
-func setupMainApp(app *tview.Application, email, apiToken string, initialJQL string) *tview.Flex {
+func setupMainApp(ctx context.Context, app *tview.Application, client *JiraClient, cfg Config) *tview.Flex {
 	tview.Styles.PrimitiveBackgroundColor = tcell.ColorDefault
 	tview.Styles.ContrastBackgroundColor = tcell.ColorBlue
 	tview.Styles.MoreContrastBackgroundColor = tcell.ColorDarkBlue
@@ -296,146 +421,903 @@ func setupMainApp(app *tview.Application, email, apiToken string, initialJQL str
 	searchField := createSearchField()
 	statusTextView := createStatusTextView()
 	detailPane := createDetailPane()
+	commentList := createCommentList()
 
 	var allIssues []Issue
 	var displayedIssues []Issue
-	var currentFilters map[string]string
+	currentFilters := issueFilters{}
+	var filterSource filterSources
+	var me *User
+	var boards []Board
+	box, outboxErr := loadOutbox()
+
+	// The starting query is the first tab, followed by the saved queries.
+	tabs := newTabBar()
+	tabs.add(&queryTab{name: "Default", query: issueQuery{jql: cfg.JQL}})
+	for _, q := range cfg.SavedQueries {
+		tabs.add(&queryTab{name: q.Name, query: issueQuery{jql: q.JQL}, savedJQL: q.JQL, sort: parseIssueSort(q.Sort)})
+	}
 
-	updateStatusFunc := func(message string, isError bool) {
-		updateStatus(app, statusTextView, message, isError)
+	// The kanban view replaces the layout while active and is re-rendered
+	// whenever the displayed issues change.
+	kanban := newKanbanView(app, statusTextView)
+	kanbanActive := false
+	var renderKanban func()
+
+	isMine := func(comment Comment) bool {
+		return me != nil && comment.Author != nil && sameUser(comment.Author, me)
+	}
+
+	showIssueDetails := func(issue Issue) {
+		detailPane.SetText(formatIssueDetails(issue))
+		renderComments(commentList, issue, isMine)
+	}
+
+	currentIssue := func() (Issue, bool) {
+		index := list.GetCurrentItem()
+		if index < 0 || index >= len(displayedIssues) {
+			return Issue{}, false
+		}
+		return displayedIssues[index], true
 	}
 
+	updateStatusFunc := statusUpdater(app, statusTextView)
+
+	// refreshListTitle shows the query and its sort, with the active filters
+	// as chips.
+	var currentQuery issueQuery
+	refreshListTitle := func() {
+		list.SetTitle(currentQuery.title() + sortTitle(tabs.current().sort) + currentFilters.chips())
+	}
+
+	// rebuilding is set while updateListFunc refills the list, so that the
+	// selection moving along doesn't count as viewing issues.
+	rebuilding := false
+
 	updateListFunc := func(searchTerm string) {
+		rebuilding = true
+		defer func() { rebuilding = false }()
+		selectedKey := ""
+		previous := list.GetCurrentItem()
+		if previous >= 0 && previous < len(displayedIssues) {
+			selectedKey = displayedIssues[previous].Key
+		}
+		offset, _ := list.GetOffset()
 		list.Clear()
 		displayedIssues = nil
-		searchTerm = strings.ToLower(searchTerm)
+		search := parseSearch(searchTerm)
+		matches := map[string]issueMatch{}
 
 		for _, issue := range allIssues {
-			matchesSearch := searchTerm == "" ||
-				strings.Contains(strings.ToLower(issue.Key), searchTerm) ||
-				strings.Contains(strings.ToLower(issue.Fields.Summary), searchTerm)
-
-			matchesFilters := true
-			if len(currentFilters) > 0 {
-				for key, value := range currentFilters {
-					if value == "" {
-						continue
-					}
-					switch key {
-					case "status":
-						if issue.Fields.Status.Name != value {
-							matchesFilters = false
-						}
-					case "issueType":
-						if issue.Fields.IssueType.Name != value {
-							matchesFilters = false
-						}
-					case "assignee":
-						assigneeName := "Unassigned"
-						if issue.Fields.Assignee != nil {
-							assigneeName = issue.Fields.Assignee.DisplayName
-						}
-						if assigneeName != value {
-							matchesFilters = false
-						}
-					}
-					if !matchesFilters {
-						break
-					}
-				}
-			}
-
-			if matchesSearch && matchesFilters {
+			match, ok := search.match(issue, me)
+			if ok && currentFilters.matches(issue) {
 				displayedIssues = append(displayedIssues, issue)
+				matches[issue.Key] = match
 			}
 		}
 
+		// Best matches first, unless the tab has a sort of its own.
+		if len(search.terms) > 0 {
+			sort.SliceStable(displayedIssues, func(i, j int) bool {
+				return matches[displayedIssues[i].Key].score > matches[displayedIssues[j].Key].score
+			})
+		}
+		sortIssues(displayedIssues, tabs.current().sort)
+
 		if len(displayedIssues) == 0 {
 			detailPane.SetText("No tickets match your criteria.")
+			commentList.Clear()
 		}
 
+		marks := tabs.current().marks
 		for _, issue := range displayedIssues {
-			statusColor := getStatusColor(issue.Fields.Status.Name)
-			mainText := fmt.Sprintf("%s%s: %s", statusColor, issue.Key, issue.Fields.Summary)
-			list.AddItem(mainText, "", 0, nil)
+			list.AddItem(formatIssueRow(issue, matches[issue.Key], marks[issue.Key]), "", 0, nil)
 		}
 		if len(displayedIssues) > 0 {
-			list.SetCurrentItem(0)
+			selected := 0
+			for i, issue := range displayedIssues {
+				if issue.Key == selectedKey {
+					selected = i
+					break
+				}
+			}
+			list.SetCurrentItem(selected)
+			if selectedKey != "" {
+				// Keep the selected issue on the same line.
+				list.SetOffset(max(offset+selected-previous, 0), 0)
+			}
+		}
+		if kanbanActive {
+			renderKanban()
+		}
+	}
+
+	// Only one fetch runs at a time: starting a new one cancels the previous
+	// one, and fetchID lets late callbacks of a superseded fetch bow out.
+	var fetchID int
+	var fetchCancel context.CancelFunc
+	var fetchSpinner *spinner
+
+	stopFetch := func() {
+		if fetchCancel != nil {
+			fetchCancel()
+			fetchCancel = nil
+		}
+		if fetchSpinner != nil {
+			fetchSpinner.Stop()
+			fetchSpinner = nil
 		}
 	}
 
+	cancelFetch := func() bool {
+		if fetchCancel == nil {
+			return false
+		}
+		fetchCancel()
+		return true
+	}
+
+	// refreshStatusTitle flags the status bar while the tab shows cached
+	// issues that could not be synced yet, and while changes are queued.
+	refreshStatusTitle := func() {
+		var flags []string
+		if since := tabs.current().staleSince; !since.IsZero() {
+			flags = append(flags, fmt.Sprintf("Cached, stale since %s", since.Local().Format("2006-01-02 15:04")))
+		}
+		if queued := len(box.pending(client.BaseURL)); queued > 0 {
+			flags = append(flags, fmt.Sprintf("%d changes queued (p to review)", queued))
+		}
+		title := ""
+		if len(flags) > 0 {
+			title = " [yellow]" + strings.Join(flags, " | ") + " "
+		}
+		statusTextView.SetTitle(title)
+	}
+	box.onChange = refreshStatusTitle
+
+	// replayQueued sends the changes queued while Jira was unreachable.
+	var replayQueued func()
+	// notifyChanges tells the user about the changes others made to their
+	// issues between before and after.
+	var notifyChanges func(before, after []Issue)
+
+	// saveCache stores the issues shown as the result of query, fetched from a
+	// sync that started at syncStart. Only JQL queries are cached.
+	saveCache := func(query issueQuery, syncStart time.Time) {
+		if query.sprint != nil {
+			return
+		}
+		var issues []Issue
+		for _, issue := range allIssues {
+			if mark := tabs.current().marks[issue.Key]; mark != markRemoved && mark != markRemovedSeen {
+				issues = append(issues, issue)
+			}
+		}
+		cached := cachedQuery{Site: client.BaseURL, JQL: query.jql, LastSync: syncStart, Issues: issues}
+		go func() {
+			if err := cached.save(); err != nil {
+				updateStatusFunc(err.Error(), true)
+			}
+		}()
+	}
+
+	// fetchIssues replaces the list with the issues of query. With fromCache,
+	// the cached issues of a JQL query are shown straight away and only the
+	// ones updated since the last sync are fetched and merged in, then those
+	// that left the query are dropped. done, if not nil, is called with the
+	// outcome unless a newer fetch superseded this one.
+	fetchIssues := func(query issueQuery, fromCache bool, done func(error)) {
+		stopFetch()
+		fetchID++
+		id := fetchID
+		fetchCtx, cancel := context.WithCancel(ctx)
+		fetchCancel = cancel
+		currentQuery = query
+		tab := tabs.current()
+		tab.query, tab.loaded, tab.staleSince, tab.marks = query, false, time.Time{}, map[string]issueMark{}
+		refreshListTitle()
+		allIssues = nil
+
+		cacheable := query.sprint == nil
+		fetch := query
+		var lastSync time.Time
+		incremental := false
+		if cacheable && fromCache {
+			if cached, ok := loadCachedQuery(client.BaseURL, query.jql); ok {
+				allIssues, tab.staleSince, lastSync = cached.Issues, cached.LastSync, cached.LastSync
+				incremental = time.Since(cached.LastSync) < maxIncrementalAge
+			}
+		}
+		if incremental {
+			fetch = issueQuery{jql: updatedSinceJQL(query.jql, lastSync)}
+		}
+		refreshStatusTitle()
+		updateListFunc(searchField.GetText())
+		if len(displayedIssues) > 0 {
+			showIssueDetails(displayedIssues[0])
+		}
+		message := "Fetching Jira tickets..."
+		if len(allIssues) > 0 {
+			message = "Syncing cached tickets..."
+		}
+		fetchSpinner = startSpinner(app, statusTextView, message)
+		spin := fetchSpinner
+		syncStart := time.Now()
+
+		// Pages are merged into the cached issues, if shown, or else appended.
+		merge := !lastSync.IsZero()
+		received := 0
+
+		go func() {
+			issues, err := fetch.fetch(fetchCtx, client, func(page []Issue) {
+				app.QueueUpdateDraw(func() {
+					if id != fetchID {
+						return
+					}
+					firstPage := len(allIssues) == 0
+					if merge {
+						received += len(page)
+						notifyChanges(allIssues, page)
+						markChanges(allIssues, page, tab.marks)
+						allIssues = mergeIssues(allIssues, page)
+						spin.SetMessage(fmt.Sprintf("Syncing tickets... %d received so far", received))
+					} else {
+						allIssues = append(allIssues, page...)
+						spin.SetMessage(fmt.Sprintf("Loading tickets... %d so far", len(allIssues)))
+					}
+					updateListFunc(searchField.GetText())
+					if firstPage && len(displayedIssues) > 0 {
+						showIssueDetails(displayedIssues[0])
+					}
+				})
+			})
+			// Updates only bring in the issues still matching the query, the
+			// keys of all of them tell which cached ones have left it and
+			// the order to show them in. This is a search of every matching
+			// key, whatever the sync brought in.
+			var keys []string
+			if err == nil && incremental {
+				keys, err = client.FetchIssueKeys(fetchCtx, query.jql)
+			}
+
+			app.QueueUpdateDraw(func() {
+				if id != fetchID {
+					return // superseded by a newer fetch
+				}
+				stopFetch()
+				tab.loaded = err == nil
+				if isUnreachable(err) && !merge && cacheable {
+					// Work offline from the cache.
+					if cached, ok := loadCachedQuery(client.BaseURL, query.jql); ok {
+						allIssues, tab.staleSince, lastSync, merge = cached.Issues, cached.LastSync, cached.LastSync, true
+						refreshStatusTitle()
+						updateListFunc(searchField.GetText())
+					}
+				}
+				removed := 0
+				if err == nil {
+					if incremental {
+						allIssues, removed = orderIssues(allIssues, keys)
+					} else {
+						// A full fetch also drops the issues that left the query.
+						allIssues = issues
+					}
+					updateListFunc(searchField.GetText())
+					tab.staleSince, tab.refreshed = time.Time{}, time.Now()
+					refreshStatusTitle()
+				}
+				switch {
+				case errors.Is(err, context.Canceled):
+					updateStatusFunc(fmt.Sprintf("Fetch cancelled, showing %d tickets loaded so far.", len(allIssues)), true)
+				case isUnreachable(err) && merge:
+					updateStatusFunc(fmt.Sprintf("Jira is unreachable, working offline from the tickets cached at %s.", lastSync.Local().Format("2006-01-02 15:04")), true)
+				case err != nil:
+					updateStatusFunc(fmt.Sprintf("Error fetching tickets: %s", describeError(err)), true)
+				case incremental:
+					updateStatusFunc(fmt.Sprintf("Synced %d tickets, %d updated and %d removed since %s.", len(allIssues), len(issues), removed, lastSync.Local().Format("2006-01-02 15:04")), false)
+				case len(issues) == 0:
+					updateStatusFunc("No tickets found.", false)
+				default:
+					updateStatusFunc(fmt.Sprintf("Loaded %d tickets.", len(issues)), false)
+				}
+				if err == nil {
+					saveCache(query, syncStart)
+					replayQueued()
+				}
+				if done != nil {
+					done(err)
+				}
+			})
+		}()
+	}
+
+	// loadIssues fetches all the issues of query afresh.
+	loadIssues := func(query issueQuery, done func(error)) {
+		fetchIssues(query, false, done)
+	}
+
+	// openIssues shows the cached issues of query, if any, while syncing them.
+	openIssues := func(query issueQuery) {
+		fetchIssues(query, true, nil)
+	}
+
 	searchField.SetChangedFunc(func(text string) {
 		updateListFunc(text)
 	})
 
+	// updateIssue replaces an issue after it was changed from the TUI and
+	// redraws its row and details in place.
+	updateIssue := func(updated Issue) {
+		for i := range allIssues {
+			if allIssues[i].Key == updated.Key {
+				allIssues[i] = updated
+			}
+		}
+		for i := range displayedIssues {
+			if displayedIssues[i].Key != updated.Key {
+				continue
+			}
+			displayedIssues[i] = updated
+			match, _ := parseSearch(searchField.GetText()).match(updated, me)
+			list.SetItemText(i, formatIssueRow(updated, match, tabs.current().marks[updated.Key]), "")
+			if list.GetCurrentItem() == i {
+				commentIndex := commentList.GetCurrentItem()
+				showIssueDetails(updated)
+				commentList.SetCurrentItem(commentIndex)
+			}
+		}
+		if kanbanActive {
+			renderKanban()
+		}
+	}
+
+	// notify tells the user about changes others made to their issues, once
+	// the changelog says who made them.
+	var screen tcell.Screen
+	app.SetBeforeDrawFunc(func(s tcell.Screen) bool {
+		screen = s
+		return false
+	})
+	beep := func() {
+		app.QueueUpdate(func() {
+			if screen != nil {
+				screen.Beep()
+			}
+		})
+	}
+	notifier := newNotifier(cfg.Notify, beep, updateStatusFunc)
+	notify := func(changes []issueChange) {
+		if len(changes) == 0 {
+			return
+		}
+		myself := me
+		go func() {
+			for _, change := range attributeChanges(ctx, client, changes, myself) {
+				if err := notifier.notify(change.notification()); err != nil {
+					updateStatusFunc(fmt.Sprintf("Error sending notification: %v", err), true)
+				}
+			}
+		}()
+	}
+	// Changes found before we know who we are, such as those the sync at
+	// startup brings in, wait until we do.
+	var unnotified []struct{ before, after []Issue }
+	notifyChanges = func(before, after []Issue) {
+		switch {
+		case len(cfg.Notify.Events) == 0:
+		case me == nil:
+			unnotified = append(unnotified, struct{ before, after []Issue }{before, after})
+		default:
+			notify(issueNotifications(before, after, me, cfg.Notify.Events))
+		}
+	}
+
+	// refreshIssues fetches the issues of the current tab again in the
+	// background and marks the new, changed and removed ones, keeping the
+	// selection and scroll position. Only a manual refresh shows a spinner.
+	refreshIssues := func(manual bool) {
+		tab := tabs.current()
+		if fetchCancel != nil && !manual {
+			return // a fetch is already running
+		}
+		if !tab.loaded || fetchCancel != nil {
+			// The last fetch failed, was cancelled or is still running: start
+			// over, from the cache unless asked for.
+			if manual {
+				loadIssues(tab.query, nil)
+			} else {
+				openIssues(tab.query)
+			}
+			return
+		}
+		fetchID++
+		id := fetchID
+		fetchCtx, cancel := context.WithCancel(ctx)
+		fetchCancel = cancel
+		if manual {
+			fetchSpinner = startSpinner(app, statusTextView, "Refreshing tickets...")
+		}
+		query := tab.query
+		syncStart := time.Now()
+
+		go func() {
+			issues, err := query.fetch(fetchCtx, client, nil)
+			app.QueueUpdateDraw(func() {
+				if id != fetchID {
+					return // superseded by a newer fetch
+				}
+				stopFetch()
+				switch {
+				case errors.Is(err, context.Canceled):
+					updateStatusFunc("Refresh cancelled.", true)
+					return
+				case err != nil:
+					updateStatusFunc(fmt.Sprintf("Error refreshing tickets: %s", describeError(err)), true)
+					return
+				}
+				notifyChanges(allIssues, issues)
+				var changes issueChanges
+				allIssues, changes = diffIssues(allIssues, issues, tab.marks)
+				tab.refreshed = time.Now()
+				updateListFunc(searchField.GetText())
+				if issue, ok := currentIssue(); ok {
+					showIssueDetails(issue)
+				}
+				saveCache(query, syncStart)
+				switch {
+				case !changes.empty():
+					updateStatusFunc(fmt.Sprintf("Refreshed at %s: %d new, %d changed, %d removed.", tab.refreshed.Format("15:04"), changes.added, changes.changed, changes.removed), false)
+				case manual:
+					updateStatusFunc(fmt.Sprintf("Refreshed %d tickets, nothing changed.", len(issues)), false)
+				}
+				replayQueued()
+			})
+		}()
+	}
+
+	// Queued changes are sent whenever a fetch shows that Jira is reachable.
+	// Those whose issue someone else updated in the meantime are held back
+	// until confirmed from the outbox view.
+	replaying := false
+	replayQueued = func() {
+		ops := box.pending(client.BaseURL)
+		if replaying || len(ops) == 0 {
+			return
+		}
+		replaying = true
+		go func() {
+			updateStatusFunc(fmt.Sprintf("Sending %d queued changes...", len(ops)), false)
+			results, issues, err := replayOutbox(ctx, client, ops)
+			app.QueueUpdateDraw(func() {
+				replaying = false
+				byID := map[int]outboxOp{}
+				for _, op := range ops {
+					byID[op.ID] = op
+				}
+				sent, held := 0, 0
+				var saveErr error
+				for _, r := range results {
+					op := byID[r.id]
+					if r.sent {
+						sent++
+						saveErr = cmp.Or(saveErr, box.remove(r.id))
+						continue
+					}
+					held++
+					op.Problem = r.problem
+					saveErr = cmp.Or(saveErr, box.update(op))
+				}
+				for _, issue := range issues {
+					updateIssue(issue)
+				}
+				switch {
+				case saveErr != nil:
+					updateStatusFunc(saveErr.Error(), true)
+				case err != nil:
+					updateStatusFunc(fmt.Sprintf("Jira is unreachable again, sent %d of %d queued changes.", sent, len(ops)), true)
+				case held > 0:
+					updateStatusFunc(fmt.Sprintf("Sent %d queued changes, %d held back (p to review).", sent, held), true)
+				default:
+					updateStatusFunc(fmt.Sprintf("Sent %d queued changes.", sent), false)
+				}
+			})
+		}()
+	}
+
+	// selectIssue moves the list selection to the issue with the given key.
+	selectIssue := func(key string) {
+		for i := range displayedIssues {
+			if displayedIssues[i].Key == key {
+				list.SetCurrentItem(i)
+			}
+		}
+	}
+
+	// switchTab shows the tab at index with the issues, search text and
+	// selection it had when it was left, loading it the first time.
+	switchTab := func(index int) {
+		if index < 0 || index >= len(tabs.tabs) || index == tabs.active {
+			return
+		}
+		leaving := tabs.current()
+		if fetchCancel != nil {
+			// Drop the unfinished fetch; the tab reloads when shown again.
+			stopFetch()
+			fetchID++
+			leaving.loaded = false
+		}
+		leaving.issues, leaving.search = allIssues, searchField.GetText()
+		if issue, ok := currentIssue(); ok {
+			leaving.selected = issue.Key
+		}
+
+		tabs.active = index
+		tabs.render()
+		tab := tabs.current()
+		allIssues, displayedIssues = tab.issues, nil
+		searchField.SetText(tab.search)
+		if !tab.loaded {
+			openIssues(tab.query)
+			return
+		}
+		currentQuery = tab.query
+		refreshListTitle()
+		refreshStatusTitle()
+		updateListFunc(tab.search)
+		selectIssue(tab.selected)
+		if issue, ok := currentIssue(); ok {
+			showIssueDetails(issue)
+		}
+		updateStatusFunc(fmt.Sprintf("%s: %d tickets.", tab.name, len(allIssues)), false)
+		if interval := cfg.refreshInterval(); interval > 0 && time.Since(tab.refreshed) >= interval {
+			refreshIssues(false)
+		}
+	}
+
+	leftColumn := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(searchField, 3, 1, true).
+		AddItem(tabs.view, 1, 0, false).
+		AddItem(list, 0, 1, false).
+		AddItem(statusTextView, 3, 0, false)
 	mainFlex := tview.NewFlex().
+		AddItem(leftColumn, 0, 1, true).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
-			AddItem(searchField, 3, 1, true).
-			AddItem(list, 0, 1, false).
-			AddItem(statusTextView, 3, 0, false), 0, 1, true).
-		AddItem(detailPane, 0, 1, false)
+			AddItem(detailPane, 0, 2, false).
+			AddItem(commentList, 0, 1, false), 0, 1, false)
+
+	returnToMain := func() {
+		if kanbanActive {
+			app.SetRoot(kanban.layout, true)
+			kanban.focus()
+			return
+		}
+		app.SetRoot(mainFlex, true).SetFocus(list)
+	}
+
+	// The JQL bar slides in above the status bar while open.
+	jqlBar := newJQLBar(loadJQLHistory(), newJQLCompleter(ctx, app, client))
+	closeJQLBar := func() {
+		leftColumn.RemoveItem(jqlBar.layout)
+		if app.GetFocus() == jqlBar.input {
+			app.SetFocus(list)
+		}
+	}
+	openJQLBar := func() {
+		jqlBar.reset(currentQuery.jql)
+		leftColumn.RemoveItem(jqlBar.layout)
+		leftColumn.AddItem(jqlBar.layout, 4, 0, true)
+		app.SetFocus(jqlBar.input)
+	}
+	jqlBar.input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			cancelFetch()
+			closeJQLBar()
+		case tcell.KeyEnter:
+			jql := strings.TrimSpace(jqlBar.input.GetText())
+			if jql == "" {
+				return
+			}
+			jqlBar.hintView.SetText("[gray]Running...")
+			tab := tabs.current()
+			previousTab, previousQuery, previousIssues := *tab, currentQuery, allIssues
+			previousSelected := ""
+			if issue, ok := currentIssue(); ok {
+				previousSelected = issue.Key
+			}
+			loadIssues(issueQuery{jql: jql}, func(err error) {
+				var jiraErr *JiraError
+				if errors.As(err, &jiraErr) && jiraErr.StatusCode == http.StatusBadRequest {
+					// Most likely a JQL syntax error: go back to the previous
+					// query and its issues, and keep the bar open to fix it.
+					*tab, currentQuery, allIssues = previousTab, previousQuery, previousIssues
+					refreshListTitle()
+					refreshStatusTitle()
+					updateListFunc(searchField.GetText())
+					selectIssue(previousSelected)
+					jqlBar.showError(jiraErr.Message())
+					app.SetFocus(jqlBar.input)
+					return
+				}
+				if err == nil {
+					if err := jqlBar.history.add(jql); err != nil {
+						updateStatusFunc(fmt.Sprintf("Error saving JQL history: %v", err), true)
+					}
+				}
+				closeJQLBar()
+			})
+		}
+	})
 
-	modal := setupActionModal(app, mainFlex, list, &displayedIssues, updateStatusFunc)
-	setupListChangedFunc(list, detailPane, searchField, statusTextView, &displayedIssues)
+	// Board column configurations, fetched once per board.
+	boardColumns := map[int][]kanbanColumn{}
+	renderKanban = func() {
+		columns := statusKanbanColumns(displayedIssues)
+		if currentQuery.board != nil {
+			if configured, ok := boardColumns[currentQuery.board.ID]; ok {
+				columns = configured
+			}
+		}
+		kanban.render(currentQuery.title(), columns, displayedIssues)
+	}
+	showKanban := func() {
+		kanbanActive = true
+		renderKanban()
+		returnToMain()
+		if board := currentQuery.board; board != nil {
+			if _, ok := boardColumns[board.ID]; !ok {
+				go func() {
+					config, err := client.FetchBoardColumns(ctx, board.ID)
+					app.QueueUpdateDraw(func() {
+						if err != nil {
+							updateStatusFunc(fmt.Sprintf("Error fetching board columns: %s", describeError(err)), true)
+							return
+						}
+						boardColumns[board.ID] = boardKanbanColumns(config)
+						if kanbanActive {
+							renderKanban()
+							kanban.focus()
+						}
+					})
+				}()
+			}
+		}
+	}
+	kanban.onClose = func() {
+		kanbanActive = false
+		returnToMain()
+	}
+	kanban.onOpen = func(issue Issue) {
+		kanban.onClose()
+		selectIssue(issue.Key)
+	}
+	kanban.onMove = func(issue Issue, target *kanbanColumn) {
+		moveIssueToColumn(ctx, app, client, box, issue, target, returnToMain, updateStatusFunc, updateIssue)
+	}
+
+	// addIssue puts a newly created issue at the top of the list and selects it.
+	addIssue := func(issue Issue) {
+		allIssues = append([]Issue{issue}, allIssues...)
+		updateListFunc(searchField.GetText())
+		selectIssue(issue.Key)
+	}
+
+	persistTabs := func() {
+		if err := saveSavedQueries(cfg.path, tabs.savedQueries()); err != nil {
+			updateStatusFunc(fmt.Sprintf("Error saving queries: %v", err), true)
+		}
+	}
+
+	// saveQuery stores the current query, or an edited one, as a named tab.
+	saveQuery := func() {
+		tab := tabs.current()
+		name, jql := "", currentQuery.jql
+		if tab.savedJQL != "" {
+			name = tab.name
+		}
+		if currentQuery.sprint != nil {
+			jql = fmt.Sprintf("sprint = %d", currentQuery.sprint.ID)
+		}
+		showSaveQueryForm(app, name, jql, returnToMain, updateStatusFunc, func(name, jql string) {
+			index := tabs.find(name)
+			if index < 0 {
+				tabs.add(&queryTab{name: name, sort: tabs.current().sort})
+				index = len(tabs.tabs) - 1
+			}
+			saved := tabs.tabs[index]
+			changed := saved.query.jql != jql || saved.query.sprint != nil
+			saved.name, saved.savedJQL = name, jql
+			saved.query = issueQuery{jql: jql}
+			if changed {
+				saved.loaded = false
+			}
+			persistTabs()
+			if index == tabs.active {
+				tabs.render()
+				if changed {
+					loadIssues(saved.query, nil)
+				}
+			} else {
+				switchTab(index)
+			}
+			updateStatusFunc(fmt.Sprintf("Saved query %q.", name), false)
+		})
+	}
+
+	// setSort sorts the current tab, saving the sort of saved queries.
+	setSort := func(s issueSort) {
+		tab := tabs.current()
+		tab.sort = s
+		if tab.savedJQL != "" {
+			persistTabs()
+		}
+		refreshListTitle()
+		updateListFunc(searchField.GetText())
+		if len(s) == 0 {
+			updateStatusFunc("Showing tickets in query order.", false)
+		} else {
+			updateStatusFunc("Sorted by "+s.label()+".", false)
+		}
+	}
+
+	// closeTab removes the current tab; saved queries are also removed from
+	// the config file.
+	closeTab := func() {
+		if tabs.active == 0 {
+			updateStatusFunc("The starting query cannot be closed.", true)
+			return
+		}
+		tab := tabs.current()
+		remove := func() {
+			index := tabs.active
+			switchTab(index - 1)
+			tabs.tabs = append(tabs.tabs[:index], tabs.tabs[index+1:]...)
+			tabs.render()
+			if tab.savedJQL != "" {
+				persistTabs()
+			}
+		}
+		if tab.savedJQL == "" {
+			remove()
+			return
+		}
+		confirmRemoveTab(app, tab.name, returnToMain, remove)
+	}
+
+	actions := []issueAction{
+		{label: "Transition", run: func(issue Issue) {
+			showTransitionPicker(ctx, app, client, box, issue, returnToMain, updateStatusFunc, updateIssue)
+		}},
+		{label: "Assign", run: func(issue Issue) {
+			showAssignPicker(ctx, app, client, box, issue, me, returnToMain, updateStatusFunc, updateIssue)
+		}},
+		{label: "Add Comment", run: func(issue Issue) {
+			showCommentEditor(ctx, app, client, box, issue, nil, returnToMain, updateStatusFunc, updateIssue)
+		}},
+	}
+	modal := setupActionModal(app, client, mainFlex, list, &displayedIssues, updateStatusFunc, actions)
+	// viewIssue shows the issue selected in the list, clearing the mark a
+	// refresh may have left on it.
+	viewIssue := func(issue Issue) {
+		showIssueDetails(issue)
+		marks := tabs.current().marks
+		mark, ok := marks[issue.Key]
+		if rebuilding || !ok {
+			return
+		}
+		if viewed := mark.viewed(); viewed != 0 {
+			marks[issue.Key] = viewed
+		} else {
+			delete(marks, issue.Key)
+		}
+		match, _ := parseSearch(searchField.GetText()).match(issue, me)
+		list.SetItemText(list.GetCurrentItem(), formatIssueRow(issue, match, marks[issue.Key]), "")
+	}
+	setupListChangedFunc(list, detailPane, searchField, statusTextView, &displayedIssues, viewIssue)
+	setupCommentList(ctx, app, client, box, mainFlex, list, commentList, currentIssue, isMine, updateStatusFunc, updateIssue)
 	list.SetSelectedFunc(func(index int, mainText string, secondaryText string, shortcut rune) {
 		app.SetRoot(modal, false).SetFocus(modal)
 	})
-	setupInputCapture(app, searchField, list, modal)
+	shortcuts := map[rune]func(){
+		'r': func() { refreshIssues(true) },
+		':': openJQLBar,
+		'b': func() {
+			showBoardBrowser(ctx, app, client, &boards, returnToMain, updateStatusFunc, func(board Board, sprint Sprint) {
+				loadIssues(issueQuery{board: &board, sprint: &sprint}, nil)
+			})
+		},
+		'c': func() { app.SetFocus(commentList) },
+		'f': func() {
+			showFilterPanel(ctx, app, client, allIssues, currentFilters, &filterSource, returnToMain, updateStatusFunc, func() {
+				refreshListTitle()
+				updateListFunc(searchField.GetText())
+				updateStatusFunc(fmt.Sprintf("Showing %d of %d tickets.", len(displayedIssues), len(allIssues)), false)
+			})
+		},
+		'v': showKanban,
+		'n': func() {
+			showCreateIssueForm(ctx, app, client, returnToMain, updateStatusFunc, addIssue)
+		},
+		'[': func() { switchTab((tabs.active + len(tabs.tabs) - 1) % len(tabs.tabs)) },
+		']': func() { switchTab((tabs.active + 1) % len(tabs.tabs)) },
+		's': saveQuery,
+		'o': func() { showSortForm(app, tabs.current().sort, returnToMain, setSort) },
+		'O': func() { setSort(tabs.current().sort.reversed()) },
+		'x': closeTab,
+		'p': func() {
+			showOutbox(app, box, client.BaseURL, returnToMain, updateStatusFunc, replayQueued)
+		},
+	}
+	for i := 0; i < 9; i++ {
+		i := i
+		shortcuts[rune('1'+i)] = func() { switchTab(i) }
+	}
+	setupInputCapture(app, searchField, list, modal, cancelFetch, shortcuts)
 
 	app.SetRoot(mainFlex, true).SetFocus(searchField)
 
-	// Fetch issues using the provided JQL
+	// Look up who we are so that our own comments can be edited
 	go func() {
-		updateStatusFunc("Fetching Jira tickets...", false)
-		issues, err := FetchJiraIssues(email, apiToken, initialJQL)
+		myself, err := client.FetchMyself(ctx)
 		if err != nil {
-			app.QueueUpdateDraw(func() {
-				updateStatusFunc(fmt.Sprintf("Error fetching tickets: %v", err), true)
-			})
 			return
 		}
-
 		app.QueueUpdateDraw(func() {
-			allIssues = issues
-			if len(allIssues) == 0 {
-				updateStatusFunc("No tickets found for the provided JQL.", false)
-				return
+			me = &myself
+			if issue, ok := currentIssue(); ok {
+				renderComments(commentList, issue, isMine)
 			}
-			statusTextView.Clear()
-			updateListFunc("")
-			list.SetCurrentItem(0)
-			selectedIssue := displayedIssues[0]
-			formattedDetails := fmt.Sprintf(`[white]Key: [yellow]%s
-[white]Summary: [yellow]%s
-[white]Status: %s%s[-]
-[white]Issue Type: %s%s[-]
-[white]Assignee: [yellow]%s
-[white]Created: [yellow]%s
-[white]Updated: [yellow]%s
-
-[white]Description:
-[gray]%s`,
-				selectedIssue.Key,
-				selectedIssue.Fields.Summary,
-				getStatusColor(selectedIssue.Fields.Status.Name), selectedIssue.Fields.Status.Name,
-				getIssueTypeColor(selectedIssue.Fields.IssueType.Name), selectedIssue.Fields.IssueType.Name,
-				func() string {
-					if selectedIssue.Fields.Assignee != nil {
-						return selectedIssue.Fields.Assignee.DisplayName
-					}
-					return "Unassigned"
-				}(),
-				selectedIssue.Fields.Created.Format("2006-01-02 15:04"),
-				selectedIssue.Fields.Updated.Format("2006-01-02 15:04"),
-				func() string {
-					if selectedIssue.Fields.Description != nil {
-						return fmt.Sprintf("%v", selectedIssue.Fields.Description)
-					}
-					return "No description."
-				}(),
-			)
-			detailPane.SetText(formattedDetails)
+			for _, u := range unnotified {
+				notifyChanges(u.before, u.after)
+			}
+			unnotified = nil
 		})
 	}()
+
+	// Favourite filters become extra tabs, unless a tab has the same name
+	if cfg.ImportFavouriteFilters {
+		go func() {
+			filters, err := client.FetchFavouriteFilters(ctx)
+			app.QueueUpdateDraw(func() {
+				if err != nil {
+					updateStatusFunc(fmt.Sprintf("Error importing favourite filters: %s", describeError(err)), true)
+					return
+				}
+				for _, f := range filters {
+					if tabs.find(f.Name) < 0 && f.JQL != "" {
+						tabs.add(&queryTab{name: f.Name, query: issueQuery{jql: f.JQL}})
+					}
+				}
+			})
+		}()
+	}
+
+	// Refresh the current tab periodically
+	if interval := cfg.refreshInterval(); interval > 0 {
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					app.QueueUpdateDraw(func() {
+						if time.Since(tabs.current().refreshed) >= interval/2 {
+							refreshIssues(false)
+						}
+					})
+				}
+			}
+		}()
+	}
+
+	// Show the cached issues of the starting query, if any, while fetching the
+	// ones updated since, or else fetch them all showing each page as it arrives
+	fetchIssues(tabs.current().query, true, func(error) {
+		if outboxErr != nil {
+			updateStatusFunc(outboxErr.Error(), true)
+		}
+	})
 	return mainFlex
 }
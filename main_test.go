@@ -0,0 +1,119 @@
+package main
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// runTestApp runs app with root on a simulation screen until the test ends.
+func runTestApp(t *testing.T, app *tview.Application, root tview.Primitive) tcell.SimulationScreen {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		t.Fatal(err)
+	}
+	screen.SetSize(80, 5)
+	app.SetScreen(screen).SetRoot(root, true)
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+	t.Cleanup(func() {
+		app.Stop()
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	})
+	return screen
+}
+
+// waitForText waits until the screen of app shows want.
+func waitForText(t *testing.T, app *tview.Application, screen tcell.SimulationScreen, want string) {
+	t.Helper()
+	var shown string
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		app.QueueUpdate(func() {
+			cells, width, _ := screen.GetContents()
+			var b strings.Builder
+			for i, cell := range cells {
+				if len(cell.Runes) > 0 {
+					b.WriteRune(cell.Runes[0])
+				}
+				if (i+1)%width == 0 {
+					b.WriteRune('\n')
+				}
+			}
+			shown = b.String()
+		})
+		if strings.Contains(shown, want) {
+			return
+		}
+	}
+	t.Fatalf("the screen shows %q, want %q", shown, want)
+}
+
+func TestStatusUpdater(t *testing.T) {
+	app := tview.NewApplication()
+	view := tview.NewTextView().SetDynamicColors(true)
+	screen := runTestApp(t, app, view)
+	updateStatusFunc := statusUpdater(app, view)
+
+	// From the UI goroutine, as event handlers and queued updates do, and
+	// from another one.
+	returned := make(chan struct{})
+	go app.QueueUpdateDraw(func() {
+		updateStatusFunc("first", false)
+		updateStatusFunc("Loaded [red]3[-] tickets.", false)
+		close(returned)
+	})
+	select {
+	case <-returned:
+	case <-time.After(5 * time.Second):
+		t.Fatal("updating the status from the UI goroutine blocked")
+	}
+
+	waitForText(t, app, screen, "Loaded [red]3[-] tickets.")
+	updateStatusFunc("Error fetching tickets.", true)
+	waitForText(t, app, screen, "Error fetching tickets.")
+}
+
+func TestInputCaptureJQLShortcut(t *testing.T) {
+	app := tview.NewApplication()
+	searchField := tview.NewInputField()
+	list := tview.NewList()
+	flex := tview.NewFlex().SetDirection(tview.FlexRow).AddItem(searchField, 1, 0, true).AddItem(list, 0, 1, false)
+	var opened atomic.Int32
+	setupInputCapture(app, searchField, list, tview.NewModal(), func() bool { return false }, map[rune]func(){
+		':': func() { opened.Add(1) },
+	})
+	runTestApp(t, app, flex)
+
+	// typeKeys sends keys and waits until the search box shows text.
+	typeKeys := func(keys, text string) {
+		t.Helper()
+		for _, r := range keys {
+			app.QueueEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+		// Handled in order: once 'x' shows, the keys before it have been.
+		app.QueueEvent(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+		var shown string
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline) && shown != text+"x"; time.Sleep(10 * time.Millisecond) {
+			app.QueueUpdate(func() { shown = searchField.GetText() })
+		}
+		if shown != text+"x" {
+			t.Fatalf("after %q the search box shows %q, want %q", keys, shown, text+"x")
+		}
+		app.QueueUpdate(func() { searchField.SetText("") })
+	}
+	typeKeys(":", "")
+	if n := opened.Load(); n != 1 {
+		t.Errorf("':' in the empty search box opened the JQL bar %d times, want once", n)
+	}
+	typeKeys("s:", "s:")
+	if n := opened.Load(); n != 1 {
+		t.Errorf("typing a qualifier opened the JQL bar")
+	}
+}
@@ -0,0 +1,691 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// Jira text comes either as wiki markup (API v2 descriptions and comments) or
+// as Atlassian Document Format (API v3). Both are rendered here into tview
+// colour-tagged text; anything taken from the source is escaped.
+
+const ruleWidth = 40
+
+// renderRichText renders a description or comment body of either format.
+func renderRichText(body interface{}) string {
+	switch b := body.(type) {
+	case nil:
+		return ""
+	case string:
+		return renderWiki(b)
+	case map[string]interface{}:
+		return strings.Join(adfBlocks(adfContent(b), false), "\n")
+	default:
+		return escapeTags(fmt.Sprintf("%v", b))
+	}
+}
+
+// richTextPlain returns the text of a description or comment body without
+// any markup, for searching.
+func richTextPlain(body interface{}) string {
+	switch b := body.(type) {
+	case string:
+		return b
+	case map[string]interface{}:
+		var text strings.Builder
+		adfPlainText(b, &text)
+		return text.String()
+	}
+	return ""
+}
+
+// --- Wiki markup ---
+
+var (
+	wikiHeading    = regexp.MustCompile(`^h([1-6])\.\s*(.*)$`)
+	wikiListItem   = regexp.MustCompile(`^([*#-]+)\s+(.*)$`)
+	wikiRule       = regexp.MustCompile(`^-{4,}$`)
+	wikiBlockStart = regexp.MustCompile(`^\{(code|noformat|panel|quote|info|note|warning|tip)(?::([^}]*))?\}`)
+
+	wikiMono    = regexp.MustCompile(`^\{\{(.+?)\}\}`)
+	wikiColor   = regexp.MustCompile(`^\{color:([#\w]+)\}(.*?)\{color\}`)
+	wikiMention = regexp.MustCompile(`^\[~(?:accountid:)?([^\]]+)\]`)
+	wikiLink    = regexp.MustCompile(`^\[(?:([^\]|]*)\|)?([^\]|]+)\]`)
+	wikiImage   = regexp.MustCompile(`^!([^!\s|]+)(?:\|[^!]*)?!`)
+
+	urlPattern      = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://`)
+	issueKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[0-9]+$`)
+	// tagColor is what a colour taken from the source may look like: a name
+	// or a hex code, with nothing that could close the tag or add attributes.
+	tagColor = regexp.MustCompile(`^#?[0-9A-Za-z]+$`)
+)
+
+// wikiEffects maps the inline effect markers to tview attribute flags.
+var wikiEffects = map[byte]string{'*': "b", '_': "i", '-': "s", '+': "u"}
+
+// wikiPanelColors are the colours of the macro panels.
+var wikiPanelColors = map[string]string{
+	"panel":   "gray",
+	"quote":   "gray",
+	"info":    "blue",
+	"note":    "yellow",
+	"warning": "red",
+	"tip":     "green",
+}
+
+// renderWiki renders Jira wiki markup.
+func renderWiki(text string) string {
+	return strings.Join(wikiBlocks(strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")), "\n")
+}
+
+func wikiBlocks(lines []string) []string {
+	var out []string
+	var numbers []int // item counters of the open numbered lists, by depth
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		if m := wikiListItem.FindStringSubmatch(trimmed); m != nil && !wikiRule.MatchString(trimmed) {
+			depth := len(m[1])
+			numbers = append(numbers[:min(len(numbers), depth)], make([]int, max(0, depth-len(numbers)))...)
+			bullet := "•"
+			if m[1][depth-1] == '#' {
+				numbers[depth-1]++
+				bullet = fmt.Sprintf("%d.", numbers[depth-1])
+			}
+			out = append(out, strings.Repeat("  ", depth-1)+bullet+" "+renderWikiInline(m[2]))
+			continue
+		}
+		numbers = numbers[:0]
+
+		if m := wikiBlockStart.FindStringSubmatch(trimmed); m != nil {
+			inner, next := collectWikiBlock(lines, i, m[1], trimmed[len(m[0]):])
+			i = next
+			out = append(out, renderWikiBlock(m[1], m[2], inner)...)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(trimmed, "|"):
+			start := i
+			for i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "|") {
+				i++
+			}
+			out = append(out, renderWikiTable(lines[start:i+1])...)
+		case wikiRule.MatchString(trimmed):
+			out = append(out, "[gray]"+strings.Repeat("─", ruleWidth)+"[-]")
+		case strings.HasPrefix(trimmed, "bq. "):
+			out = append(out, "[gray]▌[-] [::i]"+renderWikiInline(trimmed[4:])+"[::I]")
+		default:
+			if m := wikiHeading.FindStringSubmatch(trimmed); m != nil {
+				out = append(out, headingLine(m[1] == "1", renderWikiInline(m[2])))
+				continue
+			}
+			out = append(out, renderWikiInline(line))
+		}
+	}
+	return out
+}
+
+// collectWikiBlock returns the lines between {name} at lines[start] and the
+// closing {name}, and the index of the closing line. rest is what follows
+// the opening tag on its line.
+func collectWikiBlock(lines []string, start int, name, rest string) ([]string, int) {
+	closing := "{" + name + "}"
+	if end := strings.Index(rest, closing); end >= 0 {
+		return []string{rest[:end]}, start
+	}
+	var inner []string
+	if strings.TrimSpace(rest) != "" {
+		inner = append(inner, rest)
+	}
+	for i := start + 1; i < len(lines); i++ {
+		if end := strings.Index(lines[i], closing); end >= 0 {
+			if before := lines[i][:end]; strings.TrimSpace(before) != "" {
+				inner = append(inner, before)
+			}
+			return inner, i
+		}
+		inner = append(inner, lines[i])
+	}
+	return inner, len(lines) - 1 // unterminated: runs to the end
+}
+
+func renderWikiBlock(name, params string, inner []string) []string {
+	if name == "code" || name == "noformat" {
+		language := ""
+		for _, p := range strings.Split(params, "|") {
+			if p != "" && !strings.Contains(p, "=") {
+				language = p
+			}
+		}
+		return codeBlockLines(language, inner)
+	}
+
+	color := wikiPanelColors[name]
+	title := ""
+	for _, p := range strings.Split(params, "|") {
+		if key, value, ok := strings.Cut(p, "="); ok && key == "title" {
+			title = value
+		}
+	}
+	if title == "" && name != "panel" && name != "quote" {
+		title = strings.ToUpper(name)
+	}
+	return panelLines(color, title, wikiBlocks(inner))
+}
+
+// renderWikiTable renders consecutive "||header||" and "|cell|" lines.
+func renderWikiTable(lines []string) []string {
+	var rows [][]string
+	var header []bool
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		isHeader := strings.HasPrefix(line, "||")
+		var cells []string
+		for _, cell := range splitWikiTableRow(line) {
+			cell = renderWikiInline(strings.TrimSpace(cell))
+			if isHeader {
+				cell = "[::b]" + cell + "[::B]"
+			}
+			cells = append(cells, cell)
+		}
+		rows = append(rows, cells)
+		header = append(header, isHeader)
+	}
+	return tableLines(rows, header)
+}
+
+// splitWikiTableRow splits a table row on its "|" and "||" separators,
+// leaving the "|" of links such as [text|url] alone.
+func splitWikiTableRow(line string) []string {
+	var cells []string
+	var cell strings.Builder
+	depth := 0
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case c == '|' && depth == 0:
+			if i > 0 {
+				cells = append(cells, cell.String())
+			}
+			cell.Reset()
+			if i+1 < len(line) && line[i+1] == '|' {
+				i++
+			}
+			continue
+		}
+		cell.WriteByte(c)
+	}
+	if strings.TrimSpace(cell.String()) != "" {
+		cells = append(cells, cell.String())
+	}
+	return cells
+}
+
+// renderWikiInline renders the inline markup of a line: text effects,
+// monospace, colours, links, mentions, images and line breaks.
+func renderWikiInline(text string) string {
+	var out, literal strings.Builder
+	flush := func() {
+		out.WriteString(escapeTags(literal.String()))
+		literal.Reset()
+	}
+	emit := func(tagged string) {
+		flush()
+		out.WriteString(tagged)
+	}
+
+	for i := 0; i < len(text); {
+		if strings.IndexByte(`{[!\*_-+`, text[i]) < 0 {
+			literal.WriteByte(text[i])
+			i++
+			continue
+		}
+		rest := text[i:]
+		if m := wikiMono.FindStringSubmatch(rest); m != nil {
+			emit("[lightgreen]" + escapeTags(m[1]) + "[-]")
+			i += len(m[0])
+			continue
+		}
+		if m := wikiColor.FindStringSubmatch(rest); m != nil {
+			emit("[" + m[1] + "]" + renderWikiInline(m[2]) + "[-]")
+			i += len(m[0])
+			continue
+		}
+		if m := wikiMention.FindStringSubmatch(rest); m != nil {
+			emit("[yellow]@" + escapeTags(m[1]) + "[-]")
+			i += len(m[0])
+			continue
+		}
+		if m := wikiLink.FindStringSubmatch(rest); m != nil && isWikiLinkTarget(m[2]) {
+			label := m[1]
+			if label == "" {
+				label = m[2]
+			}
+			emit(linkText(renderWikiInline(label), m[2]))
+			i += len(m[0])
+			continue
+		}
+		if m := wikiImage.FindStringSubmatch(rest); m != nil && (i == 0 || text[i-1] == ' ') {
+			emit("[gray]" + escapeTags("[image: "+m[1]+"]") + "[-]")
+			i += len(m[0])
+			continue
+		}
+		if strings.HasPrefix(rest, `\\`) {
+			emit("\n")
+			i += 2
+			continue
+		}
+		if flag, ok := wikiEffects[text[i]]; ok {
+			if end := wikiEffectEnd(text, i); end > 0 {
+				emit("[::" + flag + "]" + renderWikiInline(text[i+1:end]) + "[::" + strings.ToUpper(flag) + "]")
+				i = end + 1
+				continue
+			}
+		}
+		literal.WriteByte(text[i])
+		i++
+	}
+	flush()
+	return out.String()
+}
+
+// isWikiLinkTarget reports whether the target of a [label|target] link looks
+// like one: a URL, an anchor, attachment or user, or an issue key. Anything
+// else in brackets is shown as is.
+func isWikiLinkTarget(target string) bool {
+	target = strings.TrimSpace(target)
+	return urlPattern.MatchString(target) || strings.HasPrefix(target, "mailto:") ||
+		strings.HasPrefix(target, "#") || strings.HasPrefix(target, "^") || strings.HasPrefix(target, "~") ||
+		issueKeyPattern.MatchString(target)
+}
+
+// wikiEffectEnd returns the index of the marker closing the effect opened at
+// text[start], or -1. Effects start and end at word boundaries and do not
+// begin or end with a space.
+func wikiEffectEnd(text string, start int) int {
+	marker := text[start]
+	if start > 0 && isWordRune(text[start-1]) {
+		return -1
+	}
+	if start+1 >= len(text) || text[start+1] == ' ' || text[start+1] == marker {
+		return -1
+	}
+	for end := start + 2; end < len(text); end++ {
+		if text[end] != marker || text[end-1] == ' ' {
+			continue
+		}
+		if end+1 < len(text) && isWordRune(text[end+1]) {
+			continue
+		}
+		return end
+	}
+	return -1
+}
+
+func isWordRune(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// --- Atlassian Document Format ---
+
+// adfPanelColors are the colours of the ADF panel types.
+var adfPanelColors = map[string]string{
+	"info":    "blue",
+	"note":    "purple",
+	"warning": "yellow",
+	"error":   "red",
+	"success": "green",
+}
+
+func adfContent(node map[string]interface{}) []interface{} {
+	content, _ := node["content"].([]interface{})
+	return content
+}
+
+func adfAttr(node map[string]interface{}, name string) string {
+	attrs, _ := node["attrs"].(map[string]interface{})
+	switch v := attrs[name].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%g", v)
+	}
+	return ""
+}
+
+// adfBlocks renders block nodes as lines. Unless tight, blocks are separated
+// by an empty line.
+func adfBlocks(nodes []interface{}, tight bool) []string {
+	var out []string
+	for _, n := range nodes {
+		node, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		lines := adfBlock(node)
+		if len(lines) == 0 {
+			continue
+		}
+		if !tight && len(out) > 0 {
+			out = append(out, "")
+		}
+		out = append(out, lines...)
+	}
+	return out
+}
+
+func adfBlock(node map[string]interface{}) []string {
+	content := adfContent(node)
+	switch node["type"] {
+	case "paragraph":
+		return strings.Split(adfInline(content), "\n")
+	case "heading":
+		return []string{headingLine(adfAttr(node, "level") == "1", adfInline(content))}
+	case "bulletList", "orderedList", "taskList", "decisionList":
+		number := 1
+		if start := adfAttr(node, "order"); start != "" {
+			fmt.Sscan(start, &number)
+		}
+		var out []string
+		for _, item := range content {
+			itemNode, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			bullet := "•"
+			switch node["type"] {
+			case "orderedList":
+				bullet = fmt.Sprintf("%d.", number)
+				number++
+			case "taskList":
+				bullet = "☐"
+				if adfAttr(itemNode, "state") == "DONE" {
+					bullet = "☑"
+				}
+			case "decisionList":
+				bullet = "◆"
+			}
+			var lines []string
+			if itemNode["type"] == "taskItem" || itemNode["type"] == "decisionItem" {
+				lines = strings.Split(adfInline(adfContent(itemNode)), "\n")
+			} else {
+				lines = adfBlocks(adfContent(itemNode), true)
+			}
+			out = append(out, indentLines(bullet+" ", lines)...)
+		}
+		return out
+	case "codeBlock":
+		var code strings.Builder
+		for _, n := range content {
+			if text, ok := n.(map[string]interface{}); ok {
+				code.WriteString(fmt.Sprint(text["text"]))
+			}
+		}
+		return codeBlockLines(adfAttr(node, "language"), strings.Split(code.String(), "\n"))
+	case "blockquote":
+		return panelLines("gray", "", adfBlocks(content, false))
+	case "panel":
+		panelType := adfAttr(node, "panelType")
+		color, ok := adfPanelColors[panelType]
+		if !ok {
+			color = "gray"
+		}
+		return panelLines(color, strings.ToUpper(panelType), adfBlocks(content, false))
+	case "expand", "nestedExpand":
+		return panelLines("gray", adfAttr(node, "title"), adfBlocks(content, false))
+	case "rule":
+		return []string{"[gray]" + strings.Repeat("─", ruleWidth) + "[-]"}
+	case "table":
+		var rows [][]string
+		var header []bool
+		for _, r := range content {
+			row, ok := r.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			var cells []string
+			isHeader := false
+			for _, c := range adfContent(row) {
+				cell, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				text := strings.Join(adfBlocks(adfContent(cell), true), " ")
+				if cell["type"] == "tableHeader" {
+					isHeader = true
+					text = "[::b]" + text + "[::B]"
+				}
+				cells = append(cells, text)
+			}
+			rows = append(rows, cells)
+			header = append(header, isHeader)
+		}
+		return tableLines(rows, header)
+	case "mediaSingle", "mediaGroup", "media":
+		return []string{"[gray]" + escapeTags("[attachment]") + "[-]"}
+	}
+	if content != nil {
+		return adfBlocks(content, false)
+	}
+	if text := adfInline([]interface{}{node}); text != "" {
+		return strings.Split(text, "\n")
+	}
+	return nil
+}
+
+// adfInline renders inline nodes: text with its marks, mentions, emoji,
+// dates, status lozenges, cards and hard breaks.
+func adfInline(nodes []interface{}) string {
+	var out strings.Builder
+	for _, n := range nodes {
+		node, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch node["type"] {
+		case "text":
+			text, _ := node["text"].(string)
+			out.WriteString(adfMarks(node, escapeTags(text)))
+		case "hardBreak":
+			out.WriteString("\n")
+		case "mention":
+			name := adfAttr(node, "text")
+			if !strings.HasPrefix(name, "@") {
+				name = "@" + name
+			}
+			out.WriteString("[yellow]" + escapeTags(name) + "[-]")
+		case "emoji":
+			emoji := adfAttr(node, "text")
+			if emoji == "" {
+				emoji = adfAttr(node, "shortName")
+			}
+			out.WriteString(escapeTags(emoji))
+		case "date":
+			var ms int64
+			fmt.Sscan(adfAttr(node, "timestamp"), &ms)
+			out.WriteString("[yellow]" + time.UnixMilli(ms).Format("2006-01-02") + "[-]")
+		case "status":
+			out.WriteString("[black:lightgray] " + escapeTags(strings.ToUpper(adfAttr(node, "text"))) + " [-:-]")
+		case "inlineCard", "blockCard", "embedCard":
+			url := adfAttr(node, "url")
+			out.WriteString(linkText(escapeTags(url), url))
+		default:
+			out.WriteString(adfInline(adfContent(node)))
+		}
+	}
+	return out.String()
+}
+
+// adfMarks wraps already escaped text in the tags of its marks.
+func adfMarks(node map[string]interface{}, text string) string {
+	marks, _ := node["marks"].([]interface{})
+	for _, m := range marks {
+		mark, ok := m.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch mark["type"] {
+		case "strong":
+			text = "[::b]" + text + "[::B]"
+		case "em":
+			text = "[::i]" + text + "[::I]"
+		case "strike":
+			text = "[::s]" + text + "[::S]"
+		case "underline":
+			text = "[::u]" + text + "[::U]"
+		case "code":
+			text = "[lightgreen]" + text + "[-]"
+		case "textColor":
+			if color := adfAttr(mark, "color"); tagColor.MatchString(color) {
+				text = "[" + color + "]" + text + "[-]"
+			}
+		case "link":
+			text = linkText(text, adfAttr(mark, "href"))
+		}
+	}
+	return text
+}
+
+// adfPlainText appends the text of node and its children to out.
+func adfPlainText(node map[string]interface{}, out *strings.Builder) {
+	switch node["type"] {
+	case "text":
+		text, _ := node["text"].(string)
+		out.WriteString(text)
+	case "mention", "emoji", "status":
+		out.WriteString(adfAttr(node, "text"))
+	case "hardBreak":
+		out.WriteString("\n")
+	}
+	for _, n := range adfContent(node) {
+		if child, ok := n.(map[string]interface{}); ok {
+			adfPlainText(child, out)
+		}
+	}
+	switch node["type"] {
+	case "paragraph", "heading", "listItem", "tableCell", "tableHeader", "codeBlock":
+		out.WriteString("\n")
+	}
+}
+
+// --- Shared layout ---
+
+func headingLine(top bool, text string) string {
+	if top {
+		return "[aqua::bu]" + text + "[-::-]"
+	}
+	return "[aqua::b]" + text + "[-::-]"
+}
+
+// linkText turns label into a hyperlink to url in terminals that support
+// them. URLs tview cannot carry are shown after the label instead; targets
+// that are not URLs (wiki pages, anchors, issue keys) are only coloured.
+func linkText(label, url string) string {
+	if url == "" {
+		return label
+	}
+	if !urlPattern.MatchString(url) && !strings.HasPrefix(url, "mailto:") {
+		return "[blue]" + label + "[-]"
+	}
+	if strings.ContainsAny(url, "[]") || !isASCII(url) {
+		return "[blue::u]" + label + "[-::U] [gray](" + escapeTags(url) + ")[-]"
+	}
+	return "[blue::u:" + url + "]" + label + "[-::U:-]"
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+func indentLines(first string, lines []string) []string {
+	out := make([]string, len(lines))
+	pad := strings.Repeat(" ", tview.TaggedStringWidth(first))
+	for i, line := range lines {
+		if i == 0 {
+			out[i] = first + line
+		} else {
+			out[i] = pad + line
+		}
+	}
+	return out
+}
+
+func codeBlockLines(language string, code []string) []string {
+	// Drop the blank first and last lines left by {code} on their own line.
+	for len(code) > 0 && strings.TrimSpace(code[0]) == "" {
+		code = code[1:]
+	}
+	for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+		code = code[:len(code)-1]
+	}
+	var out []string
+	if language != "" {
+		out = append(out, "[gray]┌ "+escapeTags(language)+"[-]")
+	}
+	for _, line := range code {
+		out = append(out, "[gray]│[-] [lightgreen]"+escapeTags(line)+"[-]")
+	}
+	return out
+}
+
+// panelLines draws a coloured bar left of lines, with an optional title.
+func panelLines(color, title string, lines []string) []string {
+	var out []string
+	if title != "" {
+		out = append(out, "["+color+"]▌ [::b]"+escapeTags(title)+"[::B][-]")
+	}
+	for _, line := range lines {
+		out = append(out, "["+color+"]▌[-] "+line)
+	}
+	return out
+}
+
+// tableLines lays out rendered cells in aligned columns.
+func tableLines(rows [][]string, header []bool) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], tview.TaggedStringWidth(cell))
+		}
+	}
+	var out []string
+	for r, row := range rows {
+		var line strings.Builder
+		for i, cell := range row {
+			if i > 0 {
+				line.WriteString(" [gray]│[-] ")
+			}
+			line.WriteString(cell)
+			if i < len(row)-1 {
+				line.WriteString(strings.Repeat(" ", widths[i]-tview.TaggedStringWidth(cell)))
+			}
+		}
+		out = append(out, line.String())
+		if header[r] {
+			var rule []string
+			for _, w := range widths {
+				rule = append(rule, strings.Repeat("─", w))
+			}
+			out = append(out, "[gray]"+strings.Join(rule, "─┼─")+"[-]")
+		}
+	}
+	return out
+}
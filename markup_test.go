@@ -0,0 +1,213 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderWiki(t *testing.T) {
+	tests := []struct {
+		name, text string
+		want       []string
+	}{
+		{"heading", "h1. Title", []string{"[aqua::bu]Title[-::-]"}},
+		{"subheading", "h3. *Bold* part", []string{"[aqua::b][::b]Bold[::B] part[-::-]"}},
+		{"bullets", "* one\n** two\n- three", []string{"• one", "  • two", "• three"}},
+		{"numbered", "# first\n# second\n## nested\n# third", []string{"1. first", "2. second", "  1. nested", "3. third"}},
+		{"numbering restarts", "# a\n\n# b", []string{"1. a", "", "1. b"}},
+		{"rule", "----", []string{"[gray]" + strings.Repeat("─", ruleWidth) + "[-]"}},
+		{"code", "{code:go}\nfmt.Println(\"[x]\")\n{code}", []string{
+			"[gray]┌ go[-]",
+			"[gray]│[-] [lightgreen]fmt.Println(\"[[::]x]\")[-]",
+		}},
+		{"noformat keeps markup", "{noformat}a *b*{noformat}", []string{"[gray]│[-] [lightgreen]a *b*[-]"}},
+		{"panel", "{info}\nsee *this*\n{info}", []string{"[blue]▌ [::b]INFO[::B][-]", "[blue]▌[-] see [::b]this[::B]"}},
+		{"table", "||Key||Summary||\n|A-1|[link|http://example.com]|", []string{
+			"[::b]Key[::B] [gray]│[-] [::b]Summary[::B]",
+			"[gray]" + "───" + "─┼─" + "───────" + "[-]",
+			"A-1 [gray]│[-] [blue::u:http://example.com]link[-::U:-]",
+		}},
+		{"effects", "*bold* and _it_ and a-b-c", []string{"[::b]bold[::B] and [::i]it[::I] and a-b-c"}},
+		{"monospace", "run {{go [test]}}", []string{"run [lightgreen]go [[::]test][-]"}},
+		{"escaped", "a [b] c", []string{"a [[::]b] c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := renderRichText(tt.text), strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("renderRichText(%q) =\n%q\nwant\n%q", tt.text, got, want)
+			}
+		})
+	}
+}
+
+func TestRenderWikiLinks(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"[http://example.com]", "[blue::u:http://example.com]http://example.com[-::U:-]"},
+		{"[docs|https://example.com/a?b=c]", "[blue::u:https://example.com/a?b=c]docs[-::U:-]"},
+		{"[mail|mailto:a@example.com]", "[blue::u:mailto:a@example.com]mail[-::U:-]"},
+		// URLs tview cannot carry in a tag are shown after the label.
+		{"[café|http://example.com/café]", "[blue::u]café[-::U] [gray](http://example.com/café)[-]"},
+		// Targets that are not URLs are only coloured.
+		{"[ABC-12]", "[blue]ABC-12[-]"},
+		{"[see|#anchor]", "[blue]see[-]"},
+		{"[file|^notes.txt]", "[blue]file[-]"},
+		// Brackets around anything else are text.
+		{"[not a link]", "[[::]not a link]"},
+		{"[a|b]", "[[::]a|b]"},
+		{"[~accountid:abc123]", "[yellow]@abc123[-]"},
+		{"[~jdoe]", "[yellow]@jdoe[-]"},
+	}
+	for _, tt := range tests {
+		if got := renderRichText(tt.text); got != tt.want {
+			t.Errorf("renderRichText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRenderWikiColors(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"{color:red}warm{color}", "[red]warm[-]"},
+		{"{color:#ff0000}*hot*{color}", "[#ff0000][::b]hot[::B][-]"},
+		// Anything that could close the tag or add attributes is text.
+		{"{color:red;x}warm{color}", "{color:red;x}warm{color}"},
+		{"{color:red]}warm{color}", "{color:red]}warm{color}"},
+	}
+	for _, tt := range tests {
+		if got := renderRichText(tt.text); got != tt.want {
+			t.Errorf("renderRichText(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+// adfNode returns an ADF node of the given type.
+func adfNode(nodeType string, attrs map[string]interface{}, content ...interface{}) map[string]interface{} {
+	node := map[string]interface{}{"type": nodeType}
+	if attrs != nil {
+		node["attrs"] = attrs
+	}
+	if content != nil {
+		node["content"] = content
+	}
+	return node
+}
+
+// adfText returns an ADF text node with the given marks.
+func adfText(text string, marks ...interface{}) map[string]interface{} {
+	node := map[string]interface{}{"type": "text", "text": text}
+	if marks != nil {
+		node["marks"] = marks
+	}
+	return node
+}
+
+func adfParagraph(text string) map[string]interface{} {
+	return adfNode("paragraph", nil, adfText(text))
+}
+
+func TestRenderADF(t *testing.T) {
+	link := func(href string) map[string]interface{} {
+		return adfNode("link", map[string]interface{}{"href": href})
+	}
+	color := func(c string) map[string]interface{} {
+		return adfNode("textColor", map[string]interface{}{"color": c})
+	}
+	tests := []struct {
+		name    string
+		content []interface{}
+		want    []string
+	}{
+		{"heading", []interface{}{
+			adfNode("heading", map[string]interface{}{"level": float64(1)}, adfText("Title")),
+			adfNode("heading", map[string]interface{}{"level": float64(2)}, adfText("Sub")),
+			adfParagraph("body"),
+		}, []string{"[aqua::bu]Title[-::-]", "", "[aqua::b]Sub[-::-]", "", "body"}},
+		{"bullets", []interface{}{
+			adfNode("bulletList", nil,
+				adfNode("listItem", nil, adfParagraph("a")),
+				adfNode("listItem", nil, adfParagraph("b"),
+					adfNode("bulletList", nil, adfNode("listItem", nil, adfParagraph("c"))))),
+		}, []string{"• a", "• b", "  • c"}},
+		{"numbered", []interface{}{
+			adfNode("orderedList", map[string]interface{}{"order": float64(9)},
+				adfNode("listItem", nil, adfParagraph("x")),
+				adfNode("listItem", nil, adfParagraph("y"))),
+		}, []string{"9. x", "10. y"}},
+		{"tasks", []interface{}{
+			adfNode("taskList", nil,
+				adfNode("taskItem", map[string]interface{}{"state": "DONE"}, adfText("done")),
+				adfNode("taskItem", map[string]interface{}{"state": "TODO"}, adfText("todo"))),
+		}, []string{"☑ done", "☐ todo"}},
+		{"code", []interface{}{
+			adfNode("codeBlock", map[string]interface{}{"language": "go"}, adfText("a := 1\nb[0]")),
+		}, []string{"[gray]┌ go[-]", "[gray]│[-] [lightgreen]a := 1[-]", "[gray]│[-] [lightgreen]b[[::]0][-]"}},
+		{"table", []interface{}{
+			adfNode("table", nil,
+				adfNode("tableRow", nil,
+					adfNode("tableHeader", nil, adfParagraph("K")),
+					adfNode("tableHeader", nil, adfParagraph("V"))),
+				adfNode("tableRow", nil,
+					adfNode("tableCell", nil, adfParagraph("A-1")),
+					adfNode("tableCell", nil, adfParagraph("ok")))),
+		}, []string{
+			"[::b]K[::B]   [gray]│[-] [::b]V[::B]",
+			"[gray]" + "───" + "─┼─" + "──" + "[-]",
+			"A-1 [gray]│[-] ok",
+		}},
+		{"marks", []interface{}{
+			adfNode("paragraph", nil,
+				adfText("site", link("https://example.com")),
+				adfText(" "),
+				adfText("page", link("https://example.com/wiki/[x]")),
+				adfText(" "),
+				adfText("bold", adfNode("strong", nil)),
+				adfText(" "),
+				adfText("x[1]", adfNode("code", nil))),
+		}, []string{"[blue::u:https://example.com]site[-::U:-] " +
+			"[blue::u]page[-::U] [gray](https://example.com/wiki/[[::]x])[-] " +
+			"[::b]bold[::B] [lightgreen]x[[::]1][-]"}},
+		{"colours", []interface{}{
+			adfNode("paragraph", nil,
+				adfText("hot", color("#ff0000")),
+				adfText(" "),
+				adfText("cold", color("blue]x[red"))),
+		}, []string{"[#ff0000]hot[-] cold"}},
+		{"cards and mentions", []interface{}{
+			adfNode("paragraph", nil,
+				adfNode("inlineCard", map[string]interface{}{"url": "https://example.com/browse/A-1"}),
+				adfText(" by "),
+				adfNode("mention", map[string]interface{}{"text": "Jane"})),
+		}, []string{"[blue::u:https://example.com/browse/A-1]https://example.com/browse/A-1[-::U:-] by [yellow]@Jane[-]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := adfNode("doc", nil, tt.content...)
+			if got, want := renderRichText(doc), strings.Join(tt.want, "\n"); got != want {
+				t.Errorf("got\n%q\nwant\n%q", got, want)
+			}
+		})
+	}
+}
+
+func TestRichTextPlain(t *testing.T) {
+	doc := adfNode("doc", nil,
+		adfNode("heading", map[string]interface{}{"level": float64(1)}, adfText("Title")),
+		adfNode("paragraph", nil, adfText("ask ", adfNode("strong", nil)), adfNode("mention", map[string]interface{}{"text": "@bob"})),
+	)
+	tests := []struct {
+		body interface{}
+		want string
+	}{
+		{doc, "Title\nask @bob\n"},
+		{"*wiki* is kept as is", "*wiki* is kept as is"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := richTextPlain(tt.body); got != tt.want {
+			t.Errorf("richTextPlain(%v) = %q, want %q", tt.body, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	eventComment    = "comment"
+	eventTransition = "transition"
+	eventAssign     = "assign"
+
+	notifyBell    = "bell"
+	notifyDesktop = "desktop"
+	notifyCommand = "command"
+
+	notifyTimeout = 10 * time.Second
+)
+
+// notification is a change someone else made to one of the user's issues.
+type notification struct {
+	event    string // eventComment, eventTransition or eventAssign
+	issueKey string
+	title    string
+	message  string
+}
+
+// notifier delivers notifications. It is called from a background goroutine.
+type notifier interface {
+	notify(n notification) error
+}
+
+// newNotifier returns the notifier picked in cfg. beep rings the terminal
+// bell and updateStatusFunc shows a message in the status bar. Without
+// notify-send, desktop notifications fall back to the bell, which says so
+// the first time.
+func newNotifier(cfg NotifyConfig, beep func(), updateStatusFunc func(message string, isError bool)) notifier {
+	bell := bellNotifier{beep: beep, updateStatusFunc: updateStatusFunc}
+	switch cfg.Method {
+	case notifyDesktop:
+		if _, err := exec.LookPath("notify-send"); err == nil {
+			return desktopNotifier{}
+		}
+		bell.fallback, bell.once = "notify-send is not installed, notifications are shown here", &sync.Once{}
+	case notifyCommand:
+		return commandNotifier{command: cfg.Command}
+	}
+	return bell
+}
+
+// bellNotifier rings the terminal bell and shows the notification in the
+// status bar.
+type bellNotifier struct {
+	beep             func()
+	updateStatusFunc func(message string, isError bool)
+	fallback         string     // why the configured method is not used, if it is not
+	once             *sync.Once // shows fallback with the first notification
+}
+
+func (n bellNotifier) notify(note notification) error {
+	n.beep()
+	message := fmt.Sprintf("%s: %s", note.title, note.message)
+	if n.fallback != "" {
+		n.once.Do(func() { message += " (" + n.fallback + ")" })
+	}
+	n.updateStatusFunc(message, false)
+	return nil
+}
+
+// desktopNotifier shows a desktop notification with notify-send.
+type desktopNotifier struct{}
+
+func (desktopNotifier) notify(note notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	if err := exec.CommandContext(ctx, "notify-send", "--app-name=jira", note.title, note.message).Run(); err != nil {
+		return fmt.Errorf("error running notify-send: %w", err)
+	}
+	return nil
+}
+
+// commandNotifier runs a user command with the title and message as
+// arguments. The event and issue key are passed in JIRA_EVENT and
+// JIRA_ISSUE_KEY.
+type commandNotifier struct {
+	command string
+}
+
+func (n commandNotifier) notify(note notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+	// The command may contain arguments, e.g. "terminal-notifier -sound default".
+	args := append(strings.Fields(n.command), note.title, note.message)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), "JIRA_EVENT="+note.event, "JIRA_ISSUE_KEY="+note.issueKey)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("error running %s: %w", args[0], err)
+	}
+	return nil
+}
+
+// issueChange is a difference between two versions of an issue that may be
+// worth a notification.
+type issueChange struct {
+	event  string
+	before Issue
+	after  Issue
+	by     *User // who made it, if known
+	text   string
+}
+
+// issueNotifications compares the issues before and after a refresh and
+// returns the enabled events on the user's issues: those assigned to or
+// reported by me, before or after. Comments are only kept when written by
+// someone else; transitions and reassignments are checked by
+// attributeChanges.
+func issueNotifications(before, after []Issue, me *User, events []string) []issueChange {
+	if me == nil || len(events) == 0 {
+		return nil
+	}
+	known := make(map[string]Issue, len(before))
+	for _, issue := range before {
+		known[issue.Key] = issue
+	}
+	mine := func(issue Issue) bool {
+		return sameUser(issue.Fields.Assignee, me) || sameUser(issue.Fields.Reporter, me)
+	}
+
+	var changes []issueChange
+	for _, issue := range after {
+		old, ok := known[issue.Key]
+		if !ok || !(mine(old) || mine(issue)) || old.Fields.Updated.Equal(issue.Fields.Updated.Time) {
+			continue
+		}
+		if slices.Contains(events, eventComment) && issue.Fields.Comments != nil {
+			seen := map[string]bool{}
+			if old.Fields.Comments != nil {
+				for _, c := range old.Fields.Comments.Comments {
+					seen[c.ID] = true
+				}
+			}
+			for _, c := range issue.Fields.Comments.Comments {
+				if seen[c.ID] || c.Author == nil || sameUser(c.Author, me) {
+					continue
+				}
+				changes = append(changes, issueChange{event: eventComment, before: old, after: issue, by: c.Author,
+					text: truncate(strings.Join(strings.Fields(c.Body), " "), 120)})
+			}
+		}
+		if slices.Contains(events, eventTransition) && old.Fields.Status.Name != issue.Fields.Status.Name {
+			changes = append(changes, issueChange{event: eventTransition, before: old, after: issue,
+				text: fmt.Sprintf("%s → %s", old.Fields.Status.Name, issue.Fields.Status.Name)})
+		}
+		if slices.Contains(events, eventAssign) && !sameUser(old.Fields.Assignee, issue.Fields.Assignee) {
+			to := "Unassigned"
+			if issue.Fields.Assignee != nil {
+				to = "Assigned to " + issue.Fields.Assignee.DisplayName
+			}
+			changes = append(changes, issueChange{event: eventAssign, before: old, after: issue, text: to})
+		}
+	}
+	return changes
+}
+
+// attributeChanges looks up in the changelog who transitioned or reassigned
+// the issues, dropping the changes made by me. When the changelog cannot be
+// fetched the change is kept without an author.
+func attributeChanges(ctx context.Context, client *JiraClient, changes []issueChange, me *User) []issueChange {
+	changelogs := map[string][]ChangelogEntry{}
+	var kept []issueChange
+	for _, change := range changes {
+		if change.event == eventComment {
+			kept = append(kept, change)
+			continue
+		}
+		key := change.after.Key
+		entries, ok := changelogs[key]
+		if !ok {
+			entries, _ = client.FetchChangelog(ctx, key)
+			changelogs[key] = entries
+		}
+		field := "status"
+		if change.event == eventAssign {
+			field = "assignee"
+		}
+		// The latest change to the field since the previous version.
+		for i := len(entries) - 1; i >= 0; i-- {
+			entry := entries[i]
+			if !entry.Created.After(change.before.Fields.Updated.Time) {
+				break
+			}
+			if slices.ContainsFunc(entry.Items, func(item ChangelogItem) bool { return item.Field == field }) {
+				change.by = entry.Author
+				break
+			}
+		}
+		if change.by == nil || !sameUser(change.by, me) {
+			kept = append(kept, change)
+		}
+	}
+	return kept
+}
+
+// notification formats the change for a notifier.
+func (c issueChange) notification() notification {
+	title := fmt.Sprintf("%s: %s", c.after.Key, truncate(c.after.Fields.Summary, 60))
+	message := c.text
+	switch {
+	case c.event == eventComment:
+		message = fmt.Sprintf("%s commented: %s", c.by.DisplayName, c.text)
+	case c.by != nil:
+		message = fmt.Sprintf("%s (by %s)", c.text, c.by.DisplayName)
+	}
+	return notification{event: c.event, issueKey: c.after.Key, title: title, message: message}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNotifierFallback(t *testing.T) {
+	t.Setenv("PATH", t.TempDir()) // no notify-send
+	beeps := 0
+	var statuses []string
+	n := newNotifier(NotifyConfig{Method: notifyDesktop}, func() { beeps++ }, func(message string, isError bool) {
+		statuses = append(statuses, message)
+	})
+
+	for _, key := range []string{"A-1", "A-2"} {
+		if err := n.notify(notification{issueKey: key, title: key, message: "commented"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []string{
+		"A-1: commented (notify-send is not installed, notifications are shown here)",
+		"A-2: commented",
+	}
+	if beeps != 2 || !reflect.DeepEqual(statuses, want) {
+		t.Errorf("%d beeps and statuses %q, want 2 and %q", beeps, statuses, want)
+	}
+}
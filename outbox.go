@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	opComment    = "comment"
+	opTransition = "transition"
+	opAssign     = "assign"
+)
+
+// outboxOp is a change made while Jira was unreachable, kept until it can be
+// sent.
+type outboxOp struct {
+	ID       int       `json:"id"`
+	Site     string    `json:"site"`
+	Kind     string    `json:"kind"` // opComment, opTransition or opAssign
+	IssueKey string    `json:"issueKey"`
+	Updated  time.Time `json:"updated"` // when the issue was last updated as far as we knew
+	Queued   time.Time `json:"queued"`
+
+	Body       string                 `json:"body,omitempty"` // the comment, also for a transition
+	Transition *Transition            `json:"transition,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`   // transition screen values
+	Assignee   *User                  `json:"assignee,omitempty"` // nil to unassign
+
+	Problem string `json:"problem,omitempty"` // why the last replay held it back
+	Force   bool   `json:"force,omitempty"`   // send even if the issue changed since
+}
+
+// describe returns a one line summary of the change.
+func (op outboxOp) describe() string {
+	switch op.Kind {
+	case opComment:
+		return fmt.Sprintf("comment %q", truncate(op.Body, 40))
+	case opTransition:
+		return fmt.Sprintf("move to %s", op.Transition.To.Name)
+	case opAssign:
+		if op.Assignee == nil {
+			return "unassign"
+		}
+		return fmt.Sprintf("assign to %s", op.Assignee.DisplayName)
+	}
+	return op.Kind
+}
+
+// send performs the change on Jira.
+func (op outboxOp) send(ctx context.Context, client *JiraClient) error {
+	switch op.Kind {
+	case opComment:
+		_, err := client.AddComment(ctx, op.IssueKey, op.Body)
+		return err
+	case opTransition:
+		return client.TransitionIssue(ctx, op.IssueKey, op.Transition.ID, op.Fields, op.Body)
+	case opAssign:
+		return client.AssignIssue(ctx, op.IssueKey, op.Assignee)
+	}
+	return fmt.Errorf("unknown change %q", op.Kind)
+}
+
+// outbox holds the changes waiting for Jira to be reachable again. It is
+// saved after every change so that nothing is lost when the tool exits, and
+// is only used from the UI goroutine.
+type outbox struct {
+	path     string
+	ops      []outboxOp
+	nextID   int
+	onChange func() // called after ops changed
+}
+
+// loadOutbox reads the outbox left by previous runs, if any.
+func loadOutbox() (*outbox, error) {
+	box := &outbox{path: filepath.Join(appDataDir(), "outbox.json"), nextID: 1}
+	data, err := os.ReadFile(box.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return box, nil
+	}
+	if err != nil {
+		return box, fmt.Errorf("error reading outbox: %w", err)
+	}
+	if err := json.Unmarshal(data, &box.ops); err != nil {
+		// Keep it aside rather than overwrite it with the next change.
+		box.ops = nil
+		os.Rename(box.path, box.path+".broken")
+		return box, fmt.Errorf("error parsing outbox, moved to %s.broken: %w", box.path, err)
+	}
+	for _, op := range box.ops {
+		box.nextID = max(box.nextID, op.ID+1)
+	}
+	return box, nil
+}
+
+func (o *outbox) save() error {
+	data, err := json.MarshalIndent(o.ops, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding outbox: %w", err)
+	}
+	if err := writeFileAtomic(o.path, data); err != nil {
+		return fmt.Errorf("error writing outbox: %w", err)
+	}
+	if o.onChange != nil {
+		o.onChange()
+	}
+	return nil
+}
+
+// pending returns the changes queued for site, oldest first.
+func (o *outbox) pending(site string) []outboxOp {
+	var ops []outboxOp
+	for _, op := range o.ops {
+		if op.Site == site {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// queue adds a change to issue to the outbox, reporting the outcome in the
+// status bar. It returns false if the change could not be saved.
+func (o *outbox) queue(client *JiraClient, issue Issue, op outboxOp, updateStatusFunc func(message string, isError bool)) bool {
+	op.ID, o.nextID = o.nextID, o.nextID+1
+	op.Site, op.IssueKey, op.Updated, op.Queued = client.BaseURL, issue.Key, issue.Fields.Updated.Time, time.Now()
+	o.ops = append(o.ops, op)
+	if err := o.save(); err != nil {
+		o.ops = o.ops[:len(o.ops)-1]
+		updateStatusFunc(err.Error(), true)
+		return false
+	}
+	updateStatusFunc(fmt.Sprintf("Jira is unreachable, %s: %s will be sent when back online.", issue.Key, op.describe()), false)
+	return true
+}
+
+// update replaces the op with the same ID, if still queued.
+func (o *outbox) update(op outboxOp) error {
+	for i := range o.ops {
+		if o.ops[i].ID == op.ID {
+			o.ops[i] = op
+			return o.save()
+		}
+	}
+	return nil
+}
+
+// remove drops the op with the given ID.
+func (o *outbox) remove(id int) error {
+	for i := range o.ops {
+		if o.ops[i].ID == id {
+			o.ops = append(o.ops[:i], o.ops[i+1:]...)
+			return o.save()
+		}
+	}
+	return nil
+}
+
+// replayResult is the outcome of sending a queued change: sent, or held back
+// for the given reason.
+type replayResult struct {
+	id      int
+	sent    bool
+	problem string
+}
+
+// replayOutbox sends ops in order. A change is held back when its issue was
+// updated after the change was queued, unless forced, and so are the later
+// changes to the same issue. It returns the issues as they are after the
+// changes sent, and stops with an error as soon as Jira is unreachable.
+func replayOutbox(ctx context.Context, client *JiraClient, ops []outboxOp) ([]replayResult, []Issue, error) {
+	var results []replayResult
+	var issues []Issue
+	held := map[string]bool{}
+	written := map[string]time.Time{} // updated time after our own last change
+	for _, op := range ops {
+		if held[op.IssueKey] {
+			results = append(results, replayResult{id: op.ID, problem: "Waiting for an earlier change to " + op.IssueKey + "."})
+			continue
+		}
+
+		current, err := client.FetchIssue(ctx, op.IssueKey)
+		if isUnreachable(err) {
+			return results, issues, err
+		}
+		problem := ""
+		last, ours := written[op.IssueKey]
+		updated := current.Fields.Updated.Time
+		switch {
+		case err != nil:
+			problem = describeError(err)
+		case !op.Force && updated.After(op.Updated) && (!ours || updated.After(last)):
+			problem = fmt.Sprintf("%s was updated at %s, after this change was queued.", op.IssueKey, updated.Local().Format("2006-01-02 15:04"))
+		default:
+			if err := op.send(ctx, client); err != nil {
+				if isUnreachable(err) {
+					return results, issues, err
+				}
+				problem = describeError(err)
+			} else if issue, err := client.FetchIssue(ctx, op.IssueKey); err == nil {
+				written[op.IssueKey] = issue.Fields.Updated.Time
+				issues = append(issues, issue)
+			}
+		}
+		if problem != "" {
+			held[op.IssueKey] = true
+		}
+		results = append(results, replayResult{id: op.ID, sent: problem == "", problem: problem})
+	}
+	return results, issues, nil
+}
+
+// showOutbox lists the queued changes. Enter sends the selected one even if
+// its issue changed since it was queued, d discards it.
+func showOutbox(app *tview.Application, box *outbox, site string, returnToMain func(), updateStatusFunc func(message string, isError bool), onSend func()) {
+	list := tview.NewList()
+	list.SetBorder(true).SetTitle("Queued changes (Enter to send anyway, d to discard, Esc to close)")
+	list.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+	list.SetSecondaryTextColor(tcell.ColorGray)
+
+	var ops []outboxOp
+	render := func() {
+		index := list.GetCurrentItem()
+		list.Clear()
+		ops = box.pending(site)
+		if len(ops) == 0 {
+			list.AddItem("[gray]Nothing queued.", "", 0, nil)
+			return
+		}
+		for _, op := range ops {
+			detail := "queued " + op.Queued.Local().Format("2006-01-02 15:04")
+			if op.Problem != "" {
+				detail += " [red]" + escapeTags(op.Problem)
+			}
+			list.AddItem(fmt.Sprintf("[white]%s [aqua]%s", escapeTags(op.IssueKey), escapeTags(op.describe())), detail, 0, nil)
+		}
+		list.SetCurrentItem(index)
+	}
+	selected := func() (outboxOp, bool) {
+		index := list.GetCurrentItem()
+		if index < 0 || index >= len(ops) {
+			return outboxOp{}, false
+		}
+		return ops[index], true
+	}
+
+	list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyEscape || event.Rune() == 'q':
+			returnToMain()
+			return nil
+		case event.Key() == tcell.KeyEnter:
+			if op, ok := selected(); ok {
+				op.Force = true
+				if err := box.update(op); err != nil {
+					updateStatusFunc(err.Error(), true)
+					return nil
+				}
+				returnToMain()
+				onSend()
+			}
+			return nil
+		case event.Rune() == 'd':
+			if op, ok := selected(); ok {
+				if err := box.remove(op.ID); err != nil {
+					updateStatusFunc(err.Error(), true)
+				}
+				render()
+			}
+			return nil
+		}
+		return event
+	})
+
+	render()
+	app.SetRoot(centered(list, 100, 20), true).SetFocus(list)
+}
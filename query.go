@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+const defaultListTitle = "Your Jira Tickets (Press Enter for options)"
+
+// issueQuery describes where the issue list comes from: a JQL search or the
+// issues of a sprint picked in the board browser.
+type issueQuery struct {
+	jql    string
+	board  *Board
+	sprint *Sprint
+}
+
+// fetch loads the issues of the query, reporting each page to onPage.
+func (q issueQuery) fetch(ctx context.Context, client *JiraClient, onPage func([]Issue)) ([]Issue, error) {
+	if q.sprint != nil {
+		return client.FetchSprintIssues(ctx, q.sprint.ID, onPage)
+	}
+	return client.FetchJiraIssues(ctx, q.jql, onPage)
+}
+
+// title is shown as the title of the issue list.
+func (q issueQuery) title() string {
+	if q.sprint == nil {
+		return defaultListTitle
+	}
+	title := escapeTags(q.sprint.Name)
+	if !q.sprint.StartDate.IsZero() {
+		title += fmt.Sprintf(" | %s → %s", q.sprint.StartDate.Format("2006-01-02"), q.sprint.EndDate.Format("2006-01-02"))
+	}
+	if q.sprint.Goal != "" {
+		title += " | Goal: " + escapeTags(truncate(q.sprint.Goal, 80))
+	}
+	return title
+}
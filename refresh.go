@@ -0,0 +1,94 @@
+package main
+
+// issueMark flags an issue that changed in a refresh until it is viewed.
+type issueMark int
+
+const (
+	markNew issueMark = iota + 1
+	markChanged
+	markRemoved     // no longer matches the query, kept until viewed
+	markRemovedSeen // removed and viewed, dropped at the next refresh
+)
+
+// prefix is shown in front of the issue in the list.
+func (m issueMark) prefix() string {
+	switch m {
+	case markNew:
+		return "[green]● "
+	case markChanged:
+		return "[yellow]● "
+	case markRemoved:
+		return "[red]✗ "
+	case markRemovedSeen:
+		return "[gray]✗ "
+	}
+	return ""
+}
+
+// viewed returns the mark left once the issue has been looked at.
+func (m issueMark) viewed() issueMark {
+	if m == markRemoved || m == markRemovedSeen {
+		return markRemovedSeen
+	}
+	return 0
+}
+
+// issueChanges counts the issues marked by a refresh.
+type issueChanges struct {
+	added, changed, removed int
+}
+
+func (c issueChanges) empty() bool {
+	return c.added == 0 && c.changed == 0 && c.removed == 0
+}
+
+// markChanges marks the issues of fresh that are not in issues, or that were
+// updated since, and counts them.
+func markChanges(issues, fresh []Issue, marks map[string]issueMark) issueChanges {
+	known := make(map[string]Issue, len(issues))
+	for _, issue := range issues {
+		known[issue.Key] = issue
+	}
+	var changes issueChanges
+	for _, issue := range fresh {
+		old, ok := known[issue.Key]
+		switch {
+		case !ok:
+			marks[issue.Key] = markNew
+			changes.added++
+		case !old.Fields.Updated.Equal(issue.Fields.Updated.Time) || marks[issue.Key] == markRemoved || marks[issue.Key] == markRemovedSeen:
+			if marks[issue.Key] != markNew {
+				marks[issue.Key] = markChanged
+			}
+			changes.changed++
+		}
+	}
+	return changes
+}
+
+// diffIssues compares a fresh result of a query with the issues shown and
+// returns the issues to show next: fresh, followed by the issues that left
+// the query and have not been viewed yet. marks is updated accordingly.
+func diffIssues(issues, fresh []Issue, marks map[string]issueMark) ([]Issue, issueChanges) {
+	changes := markChanges(issues, fresh, marks)
+	inFresh := make(map[string]bool, len(fresh))
+	for _, issue := range fresh {
+		inFresh[issue.Key] = true
+	}
+	result := append([]Issue(nil), fresh...)
+	for _, issue := range issues {
+		if inFresh[issue.Key] {
+			continue
+		}
+		if marks[issue.Key] == markRemovedSeen {
+			delete(marks, issue.Key)
+			continue
+		}
+		if marks[issue.Key] != markRemoved {
+			marks[issue.Key] = markRemoved
+			changes.removed++
+		}
+		result = append(result, issue)
+	}
+	return result, changes
+}
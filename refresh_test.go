@@ -0,0 +1,106 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var refreshTime = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// updatedIssue returns an issue last updated minutes after refreshTime.
+func updatedIssue(key string, minutes int) Issue {
+	issue := testIssue(key, "")
+	issue.Fields.Updated = CustomTime{refreshTime.Add(time.Duration(minutes) * time.Minute)}
+	return issue
+}
+
+func TestMarkChanges(t *testing.T) {
+	tests := []struct {
+		name      string
+		issues    []Issue
+		fresh     []Issue
+		marks     map[string]issueMark
+		wantMarks map[string]issueMark
+		want      issueChanges
+	}{
+		{
+			name:      "unchanged",
+			issues:    []Issue{updatedIssue("A-1", 0), updatedIssue("A-2", 0)},
+			fresh:     []Issue{updatedIssue("A-2", 0), updatedIssue("A-1", 0)},
+			wantMarks: map[string]issueMark{},
+		},
+		{
+			name:      "new and updated",
+			issues:    []Issue{updatedIssue("A-1", 0), updatedIssue("A-2", 0)},
+			fresh:     []Issue{updatedIssue("A-3", 0), updatedIssue("A-1", 5), updatedIssue("A-2", 0)},
+			wantMarks: map[string]issueMark{"A-3": markNew, "A-1": markChanged},
+			want:      issueChanges{added: 1, changed: 1},
+		},
+		{
+			name:      "updated while still new",
+			issues:    []Issue{updatedIssue("A-1", 0)},
+			fresh:     []Issue{updatedIssue("A-1", 5)},
+			marks:     map[string]issueMark{"A-1": markNew},
+			wantMarks: map[string]issueMark{"A-1": markNew},
+			want:      issueChanges{changed: 1},
+		},
+		{
+			name:      "back in the query",
+			issues:    []Issue{updatedIssue("A-1", 0), updatedIssue("A-2", 0)},
+			fresh:     []Issue{updatedIssue("A-1", 0), updatedIssue("A-2", 0)},
+			marks:     map[string]issueMark{"A-1": markRemoved, "A-2": markRemovedSeen},
+			wantMarks: map[string]issueMark{"A-1": markChanged, "A-2": markChanged},
+			want:      issueChanges{changed: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marks := tt.marks
+			if marks == nil {
+				marks = map[string]issueMark{}
+			}
+			if got := markChanges(tt.issues, tt.fresh, marks); got != tt.want {
+				t.Errorf("changes = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(marks, tt.wantMarks) {
+				t.Errorf("marks = %v, want %v", marks, tt.wantMarks)
+			}
+		})
+	}
+}
+
+func TestDiffIssues(t *testing.T) {
+	issues := []Issue{updatedIssue("A-1", 0), updatedIssue("A-2", 0), updatedIssue("A-3", 0), updatedIssue("A-4", 0)}
+	marks := map[string]issueMark{"A-3": markRemoved, "A-4": markRemovedSeen}
+	fresh := []Issue{updatedIssue("A-5", 0), updatedIssue("A-1", 5)}
+
+	// A-2 left the query, A-3 left it before but has not been viewed and
+	// A-4 has: it goes.
+	got, changes := diffIssues(issues, fresh, marks)
+	if keys, want := issueKeys(got), []string{"A-5", "A-1", "A-2", "A-3"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	if want := (issueChanges{added: 1, changed: 1, removed: 1}); changes != want {
+		t.Errorf("changes = %+v, want %+v", changes, want)
+	}
+	wantMarks := map[string]issueMark{"A-5": markNew, "A-1": markChanged, "A-2": markRemoved, "A-3": markRemoved}
+	if !reflect.DeepEqual(marks, wantMarks) {
+		t.Errorf("marks = %v, want %v", marks, wantMarks)
+	}
+
+	// Once viewed, the removed issues go at the next refresh.
+	for key, mark := range marks {
+		marks[key] = mark.viewed()
+	}
+	got, changes = diffIssues(got, fresh, marks)
+	if keys, want := issueKeys(got), []string{"A-5", "A-1"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys after viewing = %v, want %v", keys, want)
+	}
+	if !changes.empty() {
+		t.Errorf("changes after viewing = %+v, want none", changes)
+	}
+	if _, ok := marks["A-2"]; ok {
+		t.Errorf("marks after viewing = %v, want A-2 dropped", marks)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"strings"
+)
+
+// searchQualifiers maps the prefixes understood by the search box to the
+// fields they filter on.
+var searchQualifiers = []struct {
+	prefix string
+	field  string
+}{
+	{"s:", "status"},
+	{"t:", "issueType"},
+	{"p:", "priority"},
+	{"@", "assignee"},
+	{"#", "labels"},
+}
+
+type searchQualifier struct {
+	field string
+	value string
+}
+
+// searchQuery is the parsed content of the search box: free-text terms,
+// matched fuzzily against the key, summary, description and comments, and
+// qualifiers such as "s:progress", "t:bug", "@alice", "#label" or "p:high"
+// that filter on a field.
+type searchQuery struct {
+	terms      []string
+	qualifiers []searchQualifier
+}
+
+func parseSearch(text string) searchQuery {
+	var q searchQuery
+	for _, word := range strings.Fields(text) {
+		qualified := false
+		for _, sq := range searchQualifiers {
+			if len(word) > len(sq.prefix) && strings.HasPrefix(strings.ToLower(word), sq.prefix) {
+				q.qualifiers = append(q.qualifiers, searchQualifier{field: sq.field, value: strings.ToLower(word[len(sq.prefix):])})
+				qualified = true
+				break
+			}
+		}
+		if !qualified {
+			q.terms = append(q.terms, word)
+		}
+	}
+	return q
+}
+
+func (q searchQuery) empty() bool {
+	return len(q.terms) == 0 && len(q.qualifiers) == 0
+}
+
+// issueMatch is how well an issue matches a search, with the rune positions
+// of the key and summary to highlight.
+type issueMatch struct {
+	score   int
+	key     []int
+	summary []int
+}
+
+// match reports whether issue satisfies every qualifier and every term. me is
+// used for "@me" and may be nil.
+func (q searchQuery) match(issue Issue, me *User) (issueMatch, bool) {
+	var m issueMatch
+	for _, qual := range q.qualifiers {
+		if !qualifierMatches(issue, qual, me) {
+			return m, false
+		}
+	}
+	var body string
+	for _, term := range q.terms {
+		if score, positions, ok := fuzzyMatch(term, issue.Key); ok && score >= 100 {
+			m.score += score + 50
+			m.key = append(m.key, positions...)
+			continue
+		}
+
+		// A substring of the summary is the best match. Otherwise keep the
+		// better of a typo match and a scattered one with enough runes at
+		// word starts or in a row to be meaningful.
+		score, positions, ok := fuzzyMatch(term, issue.Fields.Summary)
+		if ok && score < 100 && score < 4*len([]rune(term)) {
+			ok = false
+		}
+		if typoScore, typoPositions, typoOK := typoMatch(term, issue.Fields.Summary); typoOK && (!ok || typoScore > score) {
+			score, positions, ok = typoScore, typoPositions, true
+		}
+		if ok && score >= 100 {
+			m.score += score
+			m.summary = append(m.summary, positions...)
+			continue
+		}
+
+		if body == "" {
+			body = searchableBody(issue)
+		}
+		switch {
+		case strings.Contains(body, strings.ToLower(term)):
+			m.score += 20
+		case ok:
+			m.score += score
+			m.summary = append(m.summary, positions...)
+		default:
+			if _, _, typoOK := typoMatch(term, body); !typoOK {
+				return m, false
+			}
+			m.score += 10
+		}
+	}
+	return m, true
+}
+
+// qualifierMatches checks a field qualifier as a case-insensitive substring.
+func qualifierMatches(issue Issue, qual searchQualifier, me *User) bool {
+	if qual.field == "assignee" && qual.value == "me" {
+		return me != nil && issue.Fields.Assignee != nil && sameUser(issue.Fields.Assignee, me)
+	}
+	values := filterValues(issue, qual.field)
+	if qual.field == "assignee" && issue.Fields.Assignee != nil {
+		values = append(values, issue.Fields.Assignee.Name, issue.Fields.Assignee.EmailAddress)
+	}
+	for _, v := range values {
+		if strings.Contains(strings.ToLower(v), qual.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// searchableBody returns the description and comment bodies of an issue in
+// lower case.
+func searchableBody(issue Issue) string {
+	var b strings.Builder
+	b.WriteString(richTextPlain(issue.Fields.Description))
+	if issue.Fields.Comments != nil {
+		for _, c := range issue.Fields.Comments.Comments {
+			b.WriteString("\n")
+			b.WriteString(c.Body)
+		}
+	}
+	return strings.ToLower(b.String())
+}
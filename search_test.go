@@ -0,0 +1,134 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestParseSearch(t *testing.T) {
+	got := parseSearch("login S:Progress @me #Backend t:bug p:high s: fix")
+	want := searchQuery{
+		terms: []string{"login", "s:", "fix"},
+		qualifiers: []searchQualifier{
+			{field: "status", value: "progress"},
+			{field: "assignee", value: "me"},
+			{field: "labels", value: "backend"},
+			{field: "issueType", value: "bug"},
+			{field: "priority", value: "high"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseSearch() = %+v, want %+v", got, want)
+	}
+}
+
+// searchIssues returns the issues of a small project.
+func searchIssues() []Issue {
+	me := &User{AccountID: "1", DisplayName: "Me Myself"}
+	alice := &User{AccountID: "2", DisplayName: "Alice Smith", EmailAddress: "alice@example.com"}
+	issue := func(key, summary, status string, assignee *User, labels ...string) Issue {
+		i := testIssue(key, summary)
+		i.Fields.Status.Name = status
+		i.Fields.Assignee = assignee
+		i.Fields.Labels = labels
+		return i
+	}
+	issues := []Issue{
+		issue("WEB-1", "Login page crashes", "In Progress", me, "frontend"),
+		issue("WEB-2", "Update the logging library", "To Do", alice, "backend"),
+		issue("WEB-3", "Slow production deploys", "Done", nil, "backend", "ops"),
+		issue("API-12", "Rate limit the login endpoint", "In Progress", alice),
+	}
+	issues[3].Fields.Description = "Clients retry forever when the catalogue is down."
+	return issues
+}
+
+func TestSearchQualifiers(t *testing.T) {
+	me := &User{AccountID: "1"}
+	tests := []struct {
+		search string
+		want   []string
+	}{
+		{"s:progress", []string{"WEB-1", "API-12"}},
+		{"s:done", []string{"WEB-3"}},
+		{"@me", []string{"WEB-1"}},
+		{"@alice", []string{"WEB-2", "API-12"}},
+		{"@example.com", []string{"WEB-2", "API-12"}},
+		{"#backend", []string{"WEB-2", "WEB-3"}},
+		{"#ops s:done", []string{"WEB-3"}},
+		{"#ops s:progress", nil},
+		{"@alice rate", []string{"API-12"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, issue := range searchIssues() {
+			if _, ok := parseSearch(tt.search).match(issue, me); ok {
+				got = append(got, issue.Key)
+			}
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q matches %v, want %v", tt.search, got, tt.want)
+		}
+	}
+
+	// Without knowing who the user is, @me matches nothing.
+	if _, ok := parseSearch("@me").match(searchIssues()[0], nil); ok {
+		t.Error("@me matched without a current user")
+	}
+}
+
+func TestSearchRanking(t *testing.T) {
+	tests := []struct {
+		search string
+		want   []string // matching issues, best first
+	}{
+		// Key, then summary substrings, the earlier the better, then
+		// scattered runes.
+		{"web-3", []string{"WEB-3"}},
+		{"log", []string{"WEB-1", "WEB-2", "API-12"}},
+		{"login", []string{"WEB-1", "API-12", "WEB-2"}},
+		// Typos in the summary, then matches in the description.
+		{"prodution", []string{"WEB-3"}},
+		{"catalogue", []string{"API-12"}},
+		{"catalouge", []string{"API-12"}},
+		{"nothing-like-it", nil},
+	}
+	for _, tt := range tests {
+		type ranked struct {
+			key   string
+			score int
+		}
+		var matches []ranked
+		for _, issue := range searchIssues() {
+			if m, ok := parseSearch(tt.search).match(issue, nil); ok {
+				matches = append(matches, ranked{issue.Key, m.score})
+			}
+		}
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+		var got []string
+		for _, m := range matches {
+			got = append(got, m.key)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q ranks %v, want %v", tt.search, matches, tt.want)
+		}
+	}
+}
+
+func TestSearchHighlights(t *testing.T) {
+	issue := testIssue("WEB-1", "Über café login")
+	m, ok := parseSearch("café web").match(issue, nil)
+	if !ok {
+		t.Fatal("no match")
+	}
+	if want := []int{5, 6, 7, 8}; !reflect.DeepEqual(m.summary, want) {
+		t.Errorf("summary positions = %v, want %v", m.summary, want)
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(m.key, want) {
+		t.Errorf("key positions = %v, want %v", m.key, want)
+	}
+	if got, want := highlightMatches(issue.Fields.Summary, m.summary), "Über [::bu]café[::-] login"; got != want {
+		t.Errorf("highlighted summary = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// sortField is a field the issue list can be sorted by. Issues for which
+// missing reports true sort last whatever the direction.
+type sortField struct {
+	name    string
+	label   string
+	compare func(a, b Issue) int
+	missing func(Issue) bool
+}
+
+var sortFields = []sortField{
+	{name: "key", label: "Key", compare: compareIssueKeys},
+	{name: "priority", label: "Priority", compare: func(a, b Issue) int {
+		return cmp.Compare(priorityRank(a.Fields.Priority), priorityRank(b.Fields.Priority))
+	}, missing: func(i Issue) bool { return i.Fields.Priority == nil }},
+	{name: "status", label: "Status category", compare: func(a, b Issue) int {
+		return cmp.Compare(statusCategoryRank(a.Fields.Status), statusCategoryRank(b.Fields.Status))
+	}},
+	{name: "updated", label: "Updated", compare: func(a, b Issue) int {
+		return a.Fields.Updated.Compare(b.Fields.Updated.Time)
+	}},
+	{name: "created", label: "Created", compare: func(a, b Issue) int {
+		return a.Fields.Created.Compare(b.Fields.Created.Time)
+	}},
+	{name: "assignee", label: "Assignee", compare: func(a, b Issue) int {
+		return strings.Compare(strings.ToLower(a.Fields.Assignee.DisplayName), strings.ToLower(b.Fields.Assignee.DisplayName))
+	}, missing: func(i Issue) bool { return i.Fields.Assignee == nil }},
+	{name: "due", label: "Due date", compare: func(a, b Issue) int {
+		return strings.Compare(a.Fields.DueDate, b.Fields.DueDate) // YYYY-MM-DD
+	}, missing: func(i Issue) bool { return i.Fields.DueDate == "" }},
+}
+
+func findSortField(name string) (sortField, bool) {
+	for _, f := range sortFields {
+		if f.name == name {
+			return f, true
+		}
+	}
+	return sortField{}, false
+}
+
+// priorityRank orders the default Jira priorities from Lowest to Highest;
+// other priorities sit in the middle.
+func priorityRank(p *Priority) int {
+	if p == nil {
+		return 0
+	}
+	switch strings.ToLower(p.Name) {
+	case "lowest", "trivial":
+		return 1
+	case "low", "minor":
+		return 2
+	case "high", "major":
+		return 4
+	case "highest", "critical":
+		return 5
+	case "blocker":
+		return 6
+	}
+	return 3
+}
+
+// compareIssueKeys orders keys by project, then numerically.
+func compareIssueKeys(a, b Issue) int {
+	projectA, numberA, _ := strings.Cut(a.Key, "-")
+	projectB, numberB, _ := strings.Cut(b.Key, "-")
+	if c := strings.Compare(projectA, projectB); c != 0 {
+		return c
+	}
+	na, _ := strconv.Atoi(numberA)
+	nb, _ := strconv.Atoi(numberB)
+	return cmp.Compare(na, nb)
+}
+
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// issueSort is the sort of the issue list: a primary and an optional
+// secondary key. Empty keeps the order the query returned.
+type issueSort []sortKey
+
+// parseIssueSort reads a sort written as "priority desc, updated". Unknown
+// fields are ignored.
+func parseIssueSort(spec string) issueSort {
+	var s issueSort
+	for _, part := range strings.Split(spec, ",") {
+		words := strings.Fields(strings.ToLower(part))
+		if len(words) == 0 {
+			continue
+		}
+		if _, ok := findSortField(words[0]); !ok {
+			continue
+		}
+		s = append(s, sortKey{field: words[0], desc: len(words) > 1 && words[1] == "desc"})
+	}
+	if len(s) > 2 {
+		s = s[:2]
+	}
+	return s
+}
+
+// String returns the sort in the form parseIssueSort reads.
+func (s issueSort) String() string {
+	parts := make([]string, len(s))
+	for i, k := range s {
+		parts[i] = k.field
+		if k.desc {
+			parts[i] += " desc"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// label describes the sort for the list title.
+func (s issueSort) label() string {
+	parts := make([]string, 0, len(s))
+	for _, k := range s {
+		f, _ := findSortField(k.field)
+		arrow := "↑"
+		if k.desc {
+			arrow = "↓"
+		}
+		parts = append(parts, arrow+" "+f.label)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// reversed returns the sort with the direction of the primary key flipped.
+func (s issueSort) reversed() issueSort {
+	if len(s) == 0 {
+		return s
+	}
+	r := append(issueSort(nil), s...)
+	r[0].desc = !r[0].desc
+	return r
+}
+
+// sortIssues sorts issues in place. Ties keep their original order.
+func sortIssues(issues []Issue, s issueSort) {
+	if len(s) == 0 {
+		return
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		a, b := issues[i], issues[j]
+		for _, k := range s {
+			f, _ := findSortField(k.field)
+			if f.missing != nil {
+				missingA, missingB := f.missing(a), f.missing(b)
+				if missingA || missingB {
+					if missingA != missingB {
+						return missingB
+					}
+					continue
+				}
+			}
+			c := f.compare(a, b)
+			if k.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// showSortForm lets the user pick the primary and secondary sort keys.
+func showSortForm(app *tview.Application, current issueSort, returnToMain func(), onSort func(issueSort)) {
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle("Sort Tickets (Esc to cancel)")
+
+	keys := [2]sortKey{}
+	copy(keys[:], current)
+	addKey := func(i int, label, none string) {
+		options := []string{none}
+		selected := 0
+		for j, f := range sortFields {
+			options = append(options, f.label)
+			if f.name == keys[i].field {
+				selected = j + 1
+			}
+		}
+		form.AddDropDown(label, options, selected, func(option string, index int) {
+			keys[i].field = ""
+			if index > 0 {
+				keys[i].field = sortFields[index-1].name
+			}
+		})
+		form.AddCheckbox("Descending", keys[i].desc, func(checked bool) {
+			keys[i].desc = checked
+		})
+	}
+	addKey(0, "Sort by", "(query order)")
+	addKey(1, "Then by", "(none)")
+
+	form.AddButton("Apply", func() {
+		var s issueSort
+		for _, k := range keys {
+			if k.field != "" {
+				s = append(s, k)
+			}
+		}
+		returnToMain()
+		onSort(s)
+	})
+	form.AddButton("Cancel", returnToMain)
+	form.SetCancelFunc(returnToMain)
+	app.SetRoot(centered(form, 50, 13), true).SetFocus(form)
+}
+
+// sortTitle is appended to the list title when the list is sorted.
+func sortTitle(s issueSort) string {
+	if len(s) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" | Sorted %s", s.label())
+}
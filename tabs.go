@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// queryTab is a query shown as a tab above the issue list. Each tab keeps its
+// own issues, search text and selection so that switching back is instant.
+type queryTab struct {
+	name     string
+	query    issueQuery
+	savedJQL string // query stored in the config file, "" if not saved
+	sort     issueSort
+	issues   []Issue
+	search   string
+	selected string // key of the selected issue
+	loaded   bool   // issues holds the complete result of query
+	// staleSince is the last sync of the cached issues shown while they are
+	// synced, zero once they are up to date.
+	staleSince time.Time
+	refreshed  time.Time            // when issues were last fetched
+	marks      map[string]issueMark // issues changed by a refresh, until viewed
+}
+
+// tabBar renders the tabs on a single line, highlighting the active one.
+type tabBar struct {
+	view   *tview.TextView
+	tabs   []*queryTab
+	active int
+}
+
+func newTabBar() *tabBar {
+	return &tabBar{view: tview.NewTextView().SetDynamicColors(true).SetWrap(false)}
+}
+
+func (t *tabBar) current() *queryTab {
+	return t.tabs[t.active]
+}
+
+// find returns the index of the tab named name, or -1.
+func (t *tabBar) find(name string) int {
+	for i, tab := range t.tabs {
+		if strings.EqualFold(tab.name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (t *tabBar) add(tab *queryTab) {
+	t.tabs = append(t.tabs, tab)
+	t.render()
+}
+
+// savedQueries returns the tabs stored in the config file.
+func (t *tabBar) savedQueries() []SavedQuery {
+	var queries []SavedQuery
+	for _, tab := range t.tabs {
+		if tab.savedJQL != "" {
+			queries = append(queries, SavedQuery{Name: tab.name, JQL: tab.savedJQL, Sort: tab.sort.String()})
+		}
+	}
+	return queries
+}
+
+func (t *tabBar) render() {
+	var line strings.Builder
+	for i, tab := range t.tabs {
+		label := escapeTags(tab.name)
+		if i < 9 {
+			label = fmt.Sprintf("%d %s", i+1, label)
+		}
+		if i == t.active {
+			fmt.Fprintf(&line, "[black:aqua] %s [-:-] ", label)
+		} else {
+			fmt.Fprintf(&line, "[gray] %s [-] ", label)
+		}
+	}
+	t.view.SetText(line.String())
+	t.view.ScrollToBeginning()
+}
+
+// showSaveQueryForm asks for a name under which jql is saved as a tab.
+// onSave receives the name and the possibly edited query.
+func showSaveQueryForm(app *tview.Application, name, jql string, returnToMain func(), updateStatusFunc func(message string, isError bool), onSave func(name, jql string)) {
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle("Save Query as Tab (Esc to cancel)")
+	form.SetFieldBackgroundColor(tcell.ColorDefault)
+	form.AddInputField("Name", name, 0, nil, func(text string) {
+		name = text
+	})
+	form.AddInputField("JQL", jql, 0, nil, func(text string) {
+		jql = text
+	})
+	form.AddButton("Save", func() {
+		name, jql := strings.TrimSpace(name), strings.TrimSpace(jql)
+		if name == "" || jql == "" {
+			updateStatusFunc("Name and JQL are required.", true)
+			return
+		}
+		returnToMain()
+		onSave(name, jql)
+	})
+	form.AddButton("Cancel", returnToMain)
+	form.SetCancelFunc(returnToMain)
+	app.SetRoot(centered(form, 80, 9), true).SetFocus(form)
+}
+
+// confirmRemoveTab asks before a saved query is removed from the config file.
+func confirmRemoveTab(app *tview.Application, name string, returnToMain func(), onRemove func()) {
+	modal := tview.NewModal().
+		SetText(fmt.Sprintf("Remove the saved query %q from the config file?", escapeTags(name))).
+		AddButtons([]string{"Remove", "Cancel"}).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			returnToMain()
+			if buttonLabel == "Remove" {
+				onRemove()
+			}
+		})
+	app.SetRoot(modal, false).SetFocus(modal)
+}
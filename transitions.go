@@ -0,0 +1,255 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// fetchTransitions fetches the transitions available for issue, remembering
+// them so that while Jira is unreachable the ones last offered at the same
+// workflow step can be used instead.
+func fetchTransitions(ctx context.Context, client *JiraClient, issue Issue) ([]Transition, error) {
+	transitions, err := client.FetchTransitions(ctx, issue.Key)
+	if err == nil {
+		saveCachedTransitions(client.BaseURL, issue, transitions) // best effort
+		return transitions, nil
+	}
+	if cached, ok := loadCachedTransitions(client.BaseURL, issue); ok && isUnreachable(err) {
+		return cached, nil
+	}
+	return nil, err
+}
+
+// showTransitionPicker fetches the transitions available for issue, lets the
+// user pick one, asks for any screen fields it needs and performs it.
+// onTransitioned receives the issue with its new status.
+func showTransitionPicker(ctx context.Context, app *tview.Application, client *JiraClient, box *outbox, issue Issue, returnToMain func(), updateStatusFunc func(message string, isError bool), onTransitioned func(Issue)) {
+	go func() {
+		updateStatusFunc(fmt.Sprintf("Fetching transitions for %s...", issue.Key), false)
+		transitions, err := fetchTransitions(ctx, client, issue)
+		app.QueueUpdateDraw(func() {
+			if err != nil {
+				updateStatusFunc(fmt.Sprintf("Error fetching transitions: %s", describeError(err)), true)
+				return
+			}
+			if len(transitions) == 0 {
+				updateStatusFunc(fmt.Sprintf("No transitions available for %s.", issue.Key), true)
+				return
+			}
+			updateStatusFunc(fmt.Sprintf("Pick a transition for %s.", issue.Key), false)
+
+			picker := tview.NewList().ShowSecondaryText(false)
+			picker.SetBorder(true).SetTitle(fmt.Sprintf("Transition %s (Esc to cancel)", issue.Key))
+			picker.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+			width := 40
+			for _, t := range transitions {
+				t := t
+				label := fmt.Sprintf("%s [white]→ %s%s", escapeTags(t.Name), getStatusColor(t.To.Name), escapeTags(t.To.Name))
+				width = max(width, len(t.Name)+len(t.To.Name)+10)
+				picker.AddItem(label, "", 0, func() {
+					if needsTransitionScreen(t) {
+						showTransitionForm(ctx, app, client, box, issue, t, returnToMain, updateStatusFunc, onTransitioned)
+						return
+					}
+					returnToMain()
+					runTransition(ctx, app, client, box, issue, t, nil, "", updateStatusFunc, onTransitioned)
+				})
+			}
+			picker.SetDoneFunc(returnToMain)
+			app.SetRoot(centered(picker, width, len(transitions)+2), true).SetFocus(picker)
+		})
+	}()
+}
+
+// needsTransitionScreen reports whether a transition has fields worth asking
+// the user about: required ones, selectable ones such as resolution, and the
+// comment box.
+func needsTransitionScreen(t Transition) bool {
+	for key, meta := range t.Fields {
+		if key == "comment" || meta.Required || len(meta.AllowedValues) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// showTransitionForm renders the transition screen fields as a form.
+func showTransitionForm(ctx context.Context, app *tview.Application, client *JiraClient, box *outbox, issue Issue, t Transition, returnToMain func(), updateStatusFunc func(message string, isError bool), onTransitioned func(Issue)) {
+	form := tview.NewForm()
+	form.SetBorder(true).SetTitle(escapeTags(fmt.Sprintf("%s: %s → %s", issue.Key, t.Name, t.To.Name)))
+
+	var comment string
+	height := 4
+	inputs := addFieldInputs(form, t.Fields, client.isCloud(), func(key string, meta FieldMeta) bool {
+		return key != "comment" && (meta.Required || len(meta.AllowedValues) > 0)
+	})
+	if _, ok := t.Fields["comment"]; ok {
+		form.AddTextArea("Comment", "", 0, 5, 0, func(text string) {
+			comment = text
+		})
+		height += 4
+	}
+	height += form.GetFormItemCount() * 2
+
+	form.AddButton("Transition", func() {
+		values, err := inputs.values()
+		if err != nil {
+			updateStatusFunc(err.Error(), true)
+			return
+		}
+		returnToMain()
+		runTransition(ctx, app, client, box, issue, t, values, strings.TrimSpace(comment), updateStatusFunc, onTransitioned)
+	})
+	form.AddButton("Cancel", returnToMain)
+	form.SetCancelFunc(returnToMain)
+
+	app.SetRoot(centered(form, 70, height), true).SetFocus(form)
+}
+
+// runTransition performs a transition in the background, queueing it in box
+// if Jira is unreachable.
+func runTransition(ctx context.Context, app *tview.Application, client *JiraClient, box *outbox, issue Issue, t Transition, fields map[string]interface{}, comment string, updateStatusFunc func(message string, isError bool), onTransitioned func(Issue)) {
+	go func() {
+		updateStatusFunc(fmt.Sprintf("Moving %s to %s...", issue.Key, t.To.Name), false)
+		err := client.TransitionIssue(ctx, issue.Key, t.ID, fields, comment)
+		app.QueueUpdateDraw(func() {
+			if isUnreachable(err) && box.queue(client, issue, outboxOp{Kind: opTransition, Transition: &t, Fields: fields, Body: comment}, updateStatusFunc) {
+				issue.Fields.Status = t.To
+				onTransitioned(issue)
+				return
+			}
+			if err != nil {
+				updateStatusFunc(fmt.Sprintf("Error transitioning %s: %s", issue.Key, describeError(err)), true)
+				return
+			}
+			issue.Fields.Status = t.To
+			onTransitioned(issue)
+			updateStatusFunc(fmt.Sprintf("%s moved to %s.", issue.Key, t.To.Name), false)
+		})
+	}()
+}
+
+// --- Screen field inputs ---
+
+// fieldInputs collects the values of form items generated from FieldMeta.
+type fieldInputs struct {
+	values func() (map[string]interface{}, error)
+}
+
+// addFieldInputs adds a form item for every field accepted by include:
+// a drop-down for fields with allowed values, a text input otherwise.
+// Fields are ordered required first, then by name. cloud tells whether the
+// form is for Jira Cloud, see fieldInputValue.
+func addFieldInputs(form *tview.Form, fields map[string]FieldMeta, cloud bool, include func(key string, meta FieldMeta) bool) fieldInputs {
+	var keys []string
+	for key, meta := range fields {
+		if include(key, meta) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := fields[keys[i]], fields[keys[j]]
+		if a.Required != b.Required {
+			return a.Required
+		}
+		return a.Name < b.Name
+	})
+
+	selected := map[string]int{} // index into AllowedValues, -1 for none
+	texts := map[string]string{}
+	for _, key := range keys {
+		key, meta := key, fields[key]
+		label := escapeTags(meta.Name)
+		if meta.Required {
+			label += " *"
+		}
+		if len(meta.AllowedValues) == 0 {
+			form.AddInputField(label, "", 0, nil, func(text string) {
+				texts[key] = text
+			})
+			continue
+		}
+
+		var options []string
+		offset := 0
+		if !meta.Required {
+			options = append(options, "(none)")
+			offset = 1
+		}
+		for _, v := range meta.AllowedValues {
+			options = append(options, escapeTags(v.Label()))
+		}
+		selected[key] = -offset
+		form.AddDropDown(label, options, 0, func(option string, index int) {
+			selected[key] = index - offset
+		})
+	}
+
+	return fieldInputs{
+		values: func() (map[string]interface{}, error) {
+			values := map[string]interface{}{}
+			for _, key := range keys {
+				meta := fields[key]
+				if len(meta.AllowedValues) > 0 {
+					index := selected[key]
+					if index < 0 {
+						if meta.Required {
+							return nil, fmt.Errorf("%s is required.", meta.Name)
+						}
+						continue
+					}
+					option := map[string]string{"id": meta.AllowedValues[index].ID}
+					if meta.Schema.Type == "array" {
+						values[key] = []interface{}{option}
+					} else {
+						values[key] = option
+					}
+					continue
+				}
+
+				text := strings.TrimSpace(texts[key])
+				if text == "" {
+					if meta.Required {
+						return nil, fmt.Errorf("%s is required.", meta.Name)
+					}
+					continue
+				}
+				value, err := fieldInputValue(meta, text, cloud)
+				if err != nil {
+					return nil, err
+				}
+				values[key] = value
+			}
+			return values, nil
+		},
+	}
+}
+
+// fieldInputValue converts text typed by the user into the JSON value Jira
+// expects for a field of the given schema. Users are given by account ID on
+// Jira Cloud and by username on Jira Server / Data Center.
+func fieldInputValue(meta FieldMeta, text string, cloud bool) (interface{}, error) {
+	switch meta.Schema.Type {
+	case "number":
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number.", meta.Name)
+		}
+		return n, nil
+	case "array":
+		return strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' }), nil
+	case "user":
+		if cloud {
+			return map[string]string{"accountId": text}, nil
+		}
+		return map[string]string{"name": text}, nil
+	default:
+		return text, nil
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFieldInputValue(t *testing.T) {
+	tests := []struct {
+		schema  string
+		text    string
+		cloud   bool
+		want    interface{}
+		wantErr bool
+	}{
+		{schema: "string", text: "hello", want: "hello"},
+		{schema: "number", text: "2.5", want: 2.5},
+		{schema: "number", text: "two", wantErr: true},
+		{schema: "array", text: "a, b c", want: []string{"a", "b", "c"}},
+		{schema: "user", text: "5b10ac8d82e05b22cc7d4ef5", cloud: true, want: map[string]string{"accountId": "5b10ac8d82e05b22cc7d4ef5"}},
+		{schema: "user", text: "jsmith", want: map[string]string{"name": "jsmith"}},
+	}
+	for _, tt := range tests {
+		meta := FieldMeta{Name: "Field"}
+		meta.Schema.Type = tt.schema
+		got, err := fieldInputValue(meta, tt.text, tt.cloud)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) && !tt.wantErr {
+			t.Errorf("fieldInputValue(%s, %q, %v) = %#v, %v, want %#v", tt.schema, tt.text, tt.cloud, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// pickerShortcut is a fixed entry shown above the users of a picker, such as
+// "Assign to me".
+type pickerShortcut struct {
+	label string
+	run   func()
+}
+
+// showUserPicker shows users in a list with a fuzzy filter box on top, best
+// matches first. onSelected receives the chosen user, Esc calls onCancel.
+// shortcuts are listed before the users while the filter is empty.
+func showUserPicker(app *tview.Application, title string, users []User, shortcuts []pickerShortcut, onSelected func(User), onCancel func()) {
+	filterField := tview.NewInputField().SetLabel("Filter: ")
+	filterField.SetFieldBackgroundColor(tcell.ColorDefault)
+	filterField.SetLabelColor(tcell.ColorAqua)
+
+	userList := tview.NewList().ShowSecondaryText(false)
+	userList.SetSelectedBackgroundColor(tcell.ColorDarkCyan)
+
+	var shown []User
+	refresh := func(filter string) {
+		userList.Clear()
+		shown = nil
+		filter = strings.TrimSpace(filter)
+		if filter == "" {
+			for _, s := range shortcuts {
+				userList.AddItem("[yellow]"+s.label, "", 0, nil)
+			}
+		}
+
+		scores := map[int]int{}
+		var indexes []int
+		for i, u := range users {
+			nameScore, _, nameOK := fuzzyMatch(filter, u.DisplayName)
+			emailScore, _, emailOK := fuzzyMatch(filter, u.EmailAddress)
+			if !nameOK && !emailOK {
+				continue
+			}
+			scores[i] = max(nameScore, emailScore)
+			indexes = append(indexes, i)
+		}
+		sort.SliceStable(indexes, func(a, b int) bool {
+			return scores[indexes[a]] > scores[indexes[b]]
+		})
+		for _, i := range indexes {
+			shown = append(shown, users[i])
+			userList.AddItem(formatUser(users[i]), "", 0, nil)
+		}
+	}
+	refresh("")
+
+	// selectCurrent runs the shortcut or picks the user under the cursor.
+	selectCurrent := func() {
+		index := userList.GetCurrentItem()
+		if strings.TrimSpace(filterField.GetText()) == "" {
+			if index < len(shortcuts) {
+				shortcuts[index].run()
+				return
+			}
+			index -= len(shortcuts)
+		}
+		if index >= 0 && index < len(shown) {
+			onSelected(shown[index])
+		}
+	}
+
+	filterField.SetChangedFunc(refresh)
+	filterField.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEscape:
+			onCancel()
+		case tcell.KeyEnter:
+			selectCurrent()
+		case tcell.KeyTab, tcell.KeyDown:
+			app.SetFocus(userList)
+		}
+	})
+	userList.SetSelectedFunc(func(index int, mainText, secondaryText string, shortcut rune) {
+		selectCurrent()
+	})
+	userList.SetDoneFunc(onCancel)
+	userList.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyBacktab || (event.Key() == tcell.KeyUp && userList.GetCurrentItem() == 0) {
+			app.SetFocus(filterField)
+			return nil
+		}
+		return event
+	})
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(filterField, 1, 0, true).
+		AddItem(userList, 0, 1, false)
+	layout.SetBorder(true).SetTitle(title)
+	app.SetRoot(centered(layout, 60, 20), true).SetFocus(filterField)
+}
+
+// sameUser reports whether a and b are the same user, comparing account IDs
+// on Jira Cloud and usernames on Jira Server. Nil is only the same as nil.
+func sameUser(a, b *User) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.AccountID != "" || b.AccountID != "" {
+		return a.AccountID == b.AccountID
+	}
+	return a.Name == b.Name
+}
+
+func formatUser(u User) string {
+	if u.EmailAddress != "" {
+		return fmt.Sprintf("%s [gray](%s)", escapeTags(u.DisplayName), escapeTags(u.EmailAddress))
+	}
+	return escapeTags(u.DisplayName)
+}
+
+// showAssignPicker lets the user pick the assignee of issue among the users
+// assignable to it, with shortcuts to assign it to themselves or nobody.
+// While Jira is unreachable only the shortcuts are offered and the change is
+// queued in box. onAssigned receives the issue with its new assignee.
+func showAssignPicker(ctx context.Context, app *tview.Application, client *JiraClient, box *outbox, issue Issue, me *User, returnToMain func(), updateStatusFunc func(message string, isError bool), onAssigned func(Issue)) {
+	assign := func(user *User) {
+		returnToMain()
+		go func() {
+			updateStatusFunc(fmt.Sprintf("Assigning %s...", issue.Key), false)
+			err := client.AssignIssue(ctx, issue.Key, user)
+			app.QueueUpdateDraw(func() {
+				if isUnreachable(err) && box.queue(client, issue, outboxOp{Kind: opAssign, Assignee: user}, updateStatusFunc) {
+					issue.Fields.Assignee = user
+					onAssigned(issue)
+					return
+				}
+				if err != nil {
+					updateStatusFunc(fmt.Sprintf("Error assigning %s: %s", issue.Key, describeError(err)), true)
+					return
+				}
+				issue.Fields.Assignee = user
+				onAssigned(issue)
+				if user == nil {
+					updateStatusFunc(fmt.Sprintf("%s is now unassigned.", issue.Key), false)
+				} else {
+					updateStatusFunc(fmt.Sprintf("%s assigned to %s.", issue.Key, user.DisplayName), false)
+				}
+			})
+		}()
+	}
+
+	var shortcuts []pickerShortcut
+	if me != nil {
+		shortcuts = append(shortcuts, pickerShortcut{label: "Assign to me", run: func() { assign(me) }})
+	}
+	shortcuts = append(shortcuts, pickerShortcut{label: "Unassign", run: func() { assign(nil) }})
+
+	go func() {
+		updateStatusFunc(fmt.Sprintf("Fetching users assignable to %s...", issue.Key), false)
+		users, err := client.FetchAssignableUsers(ctx, "", issue.Key, "")
+		app.QueueUpdateDraw(func() {
+			if isUnreachable(err) {
+				updateStatusFunc("Jira is unreachable, only the shortcuts are available.", true)
+				showUserPicker(app, fmt.Sprintf("Assign %s (Esc to cancel)", issue.Key), nil, shortcuts, func(u User) {
+					assign(&u)
+				}, returnToMain)
+				return
+			}
+			if err != nil {
+				updateStatusFunc(fmt.Sprintf("Error fetching users: %s", describeError(err)), true)
+				return
+			}
+			updateStatusFunc(fmt.Sprintf("Loaded %d users.", len(users)), false)
+			showUserPicker(app, fmt.Sprintf("Assign %s (Esc to cancel)", issue.Key), users, shortcuts, func(u User) {
+				assign(&u)
+			}, returnToMain)
+		})
+	}()
+}